@@ -13,6 +13,7 @@ import (
 	"github.com/zhufuyi/sponge/pkg/servicerd/registry/nacos"
 
 	"lingua_exchange/internal/config"
+	"lingua_exchange/internal/handler"
 	routers "lingua_exchange/internal/routers"
 	"lingua_exchange/internal/server"
 )
@@ -38,10 +39,15 @@ func CreateServices() []app.IServer {
 
 func createWebSocketServer(cfg *config.Config) (app.IServer, error) {
 	wsRegistry, wsInstance := registerService("ws", cfg.App.Host, cfg.Server.Websocket)
-	return NewSocketServer(
-		routers.NewWebSocketRouter(),
-		WithRegistry(wsRegistry, wsInstance),
-	), nil
+	opts := []Option{WithRegistry(wsRegistry, wsInstance)}
+
+	// WebSocket 与 TCP 共用同一个消息处理器
+	h := handler.NewMessageHandler()
+	if cfg.Server.Tcp > 0 {
+		opts = append(opts, WithTcpServer(NewTcpServer(cfg.Server.Tcp, h)))
+	}
+
+	return NewSocketServer(routers.NewWebSocketRouter(h), opts...), nil
 }
 
 func createHTTPServer(cfg *config.Config) (app.IServer, error) {
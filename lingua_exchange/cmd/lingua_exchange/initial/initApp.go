@@ -22,6 +22,7 @@ import (
 
 	"lingua_exchange/configs"
 	"lingua_exchange/internal/config"
+	"lingua_exchange/internal/constant"
 	"lingua_exchange/internal/model"
 )
 
@@ -134,6 +135,13 @@ func initConfig() {
 		config.Get().App.Version = version
 	}
 
-	config.Get().App.Sid = encrypt.Md5(fmt.Sprintf("%d%s", time.Now().UnixNano(), strutil.Random(6)))
+	// 未配置固定节点ID时每次启动随机生成，Redis Streams 的消费组以节点ID命名，必须使用固定的节点ID
+	if config.Get().App.Sid == "" {
+		if config.Get().App.MessageTransport == constant.ImTransportStream {
+			panic("app.sid must be set when app.messageTransport is stream")
+		}
+
+		config.Get().App.Sid = encrypt.Md5(fmt.Sprintf("%d%s", time.Now().UnixNano(), strutil.Random(6)))
+	}
 
 }
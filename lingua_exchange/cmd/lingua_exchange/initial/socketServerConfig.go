@@ -16,8 +16,10 @@ import (
 	"github.com/zhufuyi/sponge/pkg/logger"
 	"github.com/zhufuyi/sponge/pkg/servicerd/registry"
 	"golang.org/x/sync/errgroup"
+	"lingua_exchange/internal/chat/payload"
 	"lingua_exchange/internal/chat/subscribe"
 	"lingua_exchange/internal/config"
+	"lingua_exchange/internal/constant"
 	"lingua_exchange/pkg/socket"
 )
 
@@ -36,6 +38,7 @@ type SocketServerConfig struct {
 	registry        registry.Registry
 	instance        *registry.ServiceInstance
 	subscribeServer *subscribe.Server
+	tcpServer       *TcpServerConfig
 }
 
 func (s *SocketServerConfig) String() string {
@@ -52,6 +55,13 @@ func WithRegistry(reg registry.Registry, instance *registry.ServiceInstance) Opt
 	}
 }
 
+// WithTcpServer 同时启动 TCP 长连接服务
+func WithTcpServer(tcpServer *TcpServerConfig) Option {
+	return func(s *SocketServerConfig) {
+		s.tcpServer = tcpServer
+	}
+}
+
 func WithServerOption(opt ServerOption) Option {
 	return func(s *SocketServerConfig) {
 		s.server = &http.Server{
@@ -79,13 +89,44 @@ func NewSocketServer(engine *gin.Engine, opts ...Option) *SocketServerConfig {
 	return s
 }
 
+// channelOptions 读取渠道的心跳及 ACK 配置
+func (s *SocketServerConfig) channelOptions(name string) *socket.ChannelOptions {
+	conf, ok := s.Config.Socket.Channels[name]
+	if !ok {
+		return nil
+	}
+
+	return &socket.ChannelOptions{
+		Workers:           conf.Workers,
+		HeartbeatInterval: time.Duration(conf.HeartbeatInterval) * time.Second,
+		HeartbeatTimeout:  time.Duration(conf.HeartbeatTimeout) * time.Second,
+		AckDelay:          time.Duration(conf.AckDelay) * time.Second,
+		AckMaxDelay:       time.Duration(conf.AckMaxDelay) * time.Second,
+		AckRetry:          conf.AckRetry,
+	}
+}
+
 func (s *SocketServerConfig) Start() error {
 	if err := s.validate(); err != nil {
 		return fmt.Errorf("invalid server config: %w", err)
 	}
 
+	// 注册 Protobuf 编码的下行事件类型
+	payload.Register()
+
 	eg, groupCtx := errgroup.WithContext(s.ctx)
-	socket.Initialize(groupCtx, eg, s.handleError)
+	socket.Initialize(groupCtx, eg, s.handleError, s.channelOptions(constant.ImChannelChat))
+
+	// 注册配置的其它渠道
+	for name, conf := range s.Config.Socket.Channels {
+		if name == constant.ImChannelChat {
+			continue
+		}
+
+		if _, err := socket.Session.Register(name, conf.Buffer, s.channelOptions(name)); err != nil {
+			return err
+		}
+	}
 
 	if err := s.registerService(); err != nil {
 		return err
@@ -114,6 +155,12 @@ func (s *SocketServerConfig) Stop() error {
 		s.cancel()
 	}
 
+	if s.tcpServer != nil {
+		if err := s.tcpServer.Stop(); err != nil {
+			log.Printf("Failed to stop tcp server: %v", err)
+		}
+	}
+
 	if err := s.deregisterService(); err != nil {
 		log.Printf("Failed to deregister imService: %v", err)
 	}
@@ -145,6 +192,12 @@ func (s *SocketServerConfig) run(eg *errgroup.Group, ctx context.Context, c chan
 		return nil
 	})
 
+	if s.tcpServer != nil {
+		eg.Go(func() error {
+			return s.tcpServer.Start(ctx)
+		})
+	}
+
 	eg.Go(func() error {
 		select {
 		case <-ctx.Done():
@@ -0,0 +1,94 @@
+package initial
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/zhufuyi/sponge/pkg/logger"
+	"lingua_exchange/internal/handler"
+)
+
+// TcpServerConfig TCP 长连接服务，与 WebSocket 服务共用 socket.Session
+type TcpServerConfig struct {
+	addr    string
+	handler handler.MessageHandler
+
+	mu       sync.Mutex
+	listener net.Listener
+	stopped  bool // Stop 先于监听完成时，Start 不再接入连接
+}
+
+func NewTcpServer(port int, h handler.MessageHandler) *TcpServerConfig {
+	return &TcpServerConfig{
+		addr:    fmt.Sprintf(":%d", port),
+		handler: h,
+	}
+}
+
+func (t *TcpServerConfig) String() string {
+	return "tcp imService address " + t.addr
+}
+
+// Start 监听端口并接入客户端连接，ctx 结束后关闭监听
+func (t *TcpServerConfig) Start(ctx context.Context) error {
+	listener, err := net.Listen("tcp", t.addr)
+	if err != nil {
+		return fmt.Errorf("tcp server listen error: %w", err)
+	}
+
+	t.mu.Lock()
+	if t.stopped {
+		t.mu.Unlock()
+		_ = listener.Close()
+		return nil
+	}
+	t.listener = listener
+	t.mu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		_ = t.Stop()
+	}()
+
+	logger.Infof("TCP server starting on %s", t.addr)
+
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return nil
+			}
+
+			var netErr net.Error
+			if errors.As(err, &netErr) && netErr.Timeout() {
+				time.Sleep(100 * time.Millisecond)
+				continue
+			}
+
+			return fmt.Errorf("tcp server accept error: %w", err)
+		}
+
+		go t.handler.TcpConnection(conn)
+	}
+}
+
+func (t *TcpServerConfig) Stop() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.stopped = true
+	if t.listener == nil {
+		return nil
+	}
+
+	err := t.listener.Close()
+	if errors.Is(err, net.ErrClosed) {
+		return nil
+	}
+
+	return err
+}
@@ -0,0 +1,91 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"lingua_exchange/internal/model"
+	verify "lingua_exchange/pkg/jwt"
+)
+
+// fixture 压测数据，包括测试令牌及群聊
+type fixture struct {
+	db     *gorm.DB
+	tokens map[int]string
+	groups []int       // 群ID
+	member map[int]int // 用户ID -> 群ID
+}
+
+// mintTokens 为 [start, start+n) 范围内的用户签发令牌
+func mintTokens(start, n int) (map[int]string, error) {
+	verify.Init()
+
+	tokens := make(map[int]string, n)
+	for uid := start; uid < start+n; uid++ {
+		token, _, err := verify.GenerateTokens(uint64(uid))
+		if err != nil {
+			return nil, fmt.Errorf("generate token for uid %d: %w", uid, err)
+		}
+
+		tokens[uid] = token
+	}
+
+	return tokens, nil
+}
+
+// createGroups 按 size 将用户依次分组建群，每组第一个用户为群主
+func (f *fixture) createGroups(start, n, size int) error {
+	f.member = make(map[int]int, n)
+
+	now := time.Now()
+	for offset := 0; offset < n; offset += size {
+		owner := start + offset
+
+		group := &model.Group{
+			Type:      1,
+			Name:      fmt.Sprintf("loadgen-%d-%d", now.Unix(), offset/size),
+			MaxNum:    uint(size),
+			CreatorID: owner,
+		}
+		if err := f.db.Create(group).Error; err != nil {
+			return fmt.Errorf("create group: %w", err)
+		}
+		f.groups = append(f.groups, group.ID)
+
+		members := make([]*model.GroupMember, 0, size)
+		for uid := owner; uid < min(owner+size, start+n); uid++ {
+			leader := 0
+			if uid == owner {
+				leader = 2
+			}
+
+			members = append(members, &model.GroupMember{
+				GroupID:  uint(group.ID),
+				UserID:   uid,
+				Leader:   leader,
+				JoinTime: now,
+			})
+			f.member[uid] = group.ID
+		}
+
+		if err := f.db.CreateInBatches(members, 500).Error; err != nil {
+			return fmt.Errorf("create group members: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// cleanup 删除压测创建的群聊及成员
+func (f *fixture) cleanup() error {
+	if len(f.groups) == 0 {
+		return nil
+	}
+
+	if err := f.db.Unscoped().Where("group_id in ?", f.groups).Delete(&model.GroupMember{}).Error; err != nil {
+		return err
+	}
+
+	return f.db.Unscoped().Where("id in ?", f.groups).Delete(&model.Group{}).Error
+}
@@ -0,0 +1,355 @@
+// Package main 长连接压测工具。
+//
+// 为一段连续的用户ID签发测试令牌并按需建群，建立 N 个 WebSocket 或 TCP 连接，
+// 按指定速率发送携带发送时间戳的文本消息，统计连接耗时、端到端推送延迟及发送回执(rpc.reply)耗时。
+//
+// 令牌直接写入配置中的 Redis，群聊写入配置中的数据库，需与被测服务使用同一套存储。
+// 本地压测时可通过 -miniredis 启动内置 Redis 代替真实 Redis，被测服务及压测配置的 redis.dsn 均指向该地址。
+// 数据库没有内置替代：服务端只支持 MySQL/TiDB，且 dao 使用了 MySQL 方言，
+// 因此 -group-size 需要可连接的 MySQL/TiDB(与被测服务同库)，不建群的私聊压测只依赖 Redis：
+//
+//	go run ./cmd/lingua_loadgen -miniredis 127.0.0.1:6390                  # 保持运行
+//	go run ./cmd/lingua_exchange -c configs/loadgen.yml                      # redis.dsn: 127.0.0.1:6390/0
+//	go run ./cmd/lingua_loadgen -c configs/loadgen.yml -n 1000               # 私聊，无需数据库
+//	go run ./cmd/lingua_loadgen -c configs/loadgen.yml -n 1000 -group-size 100 # 群聊，需 MySQL
+//
+// 或直接压测已运行的服务：
+//
+//	go run ./cmd/lingua_loadgen -c configs/lingua_exchange.yml -n 5000 -group-size 500 -senders 20 -rate 100 -duration 2m
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/zhufuyi/sponge/pkg/ggorm"
+	"golang.org/x/time/rate"
+
+	"lingua_exchange/configs"
+	"lingua_exchange/internal/config"
+	"lingua_exchange/internal/constant"
+	"lingua_exchange/internal/model"
+	"lingua_exchange/pkg/socket/sdk"
+)
+
+// 消息内容前缀，后接发送时间(纳秒)
+const contentPrefix = "loadgen "
+
+type options struct {
+	configFile string
+	miniredis  string
+	network    string
+	addr       string
+	conns      int
+	startUid   int
+	groupSize  int
+	senders    int
+	rate       float64
+	ramp       float64
+	duration   time.Duration
+	keep       bool
+}
+
+func parseFlags() *options {
+	o := &options{}
+
+	flag.StringVar(&o.configFile, "c", configs.Path("lingua_exchange.yml"), "configuration file, used to reach the redis and database of the server under test")
+	flag.StringVar(&o.miniredis, "miniredis", "", "only start an embedded redis on this address for local runs, e.g. 127.0.0.1:6390")
+	flag.StringVar(&o.network, "network", sdk.NetworkWs, "connection type, ws or tcp")
+	flag.StringVar(&o.addr, "addr", "", "server address, default ws://127.0.0.1:8080/api/v1/ws/chat.io or 127.0.0.1:9505 for tcp")
+	flag.IntVar(&o.conns, "n", 100, "number of connections")
+	flag.IntVar(&o.startUid, "uid", 900000, "first user id, connections use [uid, uid+n)")
+	flag.IntVar(&o.groupSize, "group-size", 0, "members per group, 0 publishes private messages to the next user; requires the mysql or tidb database of the server under test")
+	flag.IntVar(&o.senders, "senders", 10, "number of connections that publish messages")
+	flag.Float64Var(&o.rate, "rate", 10, "total messages published per second")
+	flag.Float64Var(&o.ramp, "ramp", 200, "new connections per second")
+	flag.DurationVar(&o.duration, "duration", time.Minute, "publish duration")
+	flag.BoolVar(&o.keep, "keep", false, "keep the created groups after the run")
+	flag.Parse()
+
+	if o.addr == "" {
+		o.addr = "ws://127.0.0.1:8080/api/v1/ws/chat.io"
+		if o.network == sdk.NetworkTcp {
+			o.addr = "127.0.0.1:9505"
+		}
+	}
+	o.senders = max(0, min(o.senders, o.conns))
+
+	return o
+}
+
+func main() {
+	o := parseFlags()
+
+	if err := config.Init(o.configFile); err != nil {
+		exit("init config error: %v", err)
+	}
+
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+
+	if o.miniredis != "" {
+		serveMiniredis(ctx, o.miniredis)
+		return
+	}
+
+	f := &fixture{}
+
+	var err error
+	if f.tokens, err = mintTokens(o.startUid, o.conns); err != nil {
+		exit("%v", err)
+	}
+	fmt.Printf("minted %d tokens for uid [%d, %d)\n", len(f.tokens), o.startUid, o.startUid+o.conns)
+
+	if o.groupSize > 0 {
+		if driver := strings.ToLower(config.Get().Database.Driver); driver != ggorm.DBDriverMysql && driver != ggorm.DBDriverTidb {
+			exit("-group-size requires the mysql or tidb database of the server under test, got driver %q", config.Get().Database.Driver)
+		}
+
+		f.db = model.GetDB()
+		if err := f.createGroups(o.startUid, o.conns, o.groupSize); err != nil {
+			exit("%v", err)
+		}
+		fmt.Printf("created %d groups of %d members\n", len(f.groups), o.groupSize)
+
+		if !o.keep {
+			defer func() {
+				if err := f.cleanup(); err != nil {
+					fmt.Printf("cleanup groups error: %v\n", err)
+				}
+			}()
+		}
+	}
+
+	run(ctx, o, f)
+}
+
+// serveMiniredis 启动内置 Redis，直至收到退出信号
+func serveMiniredis(ctx context.Context, addr string) {
+	srv := miniredis.NewMiniRedis()
+	if err := srv.StartAddr(addr); err != nil {
+		exit("start miniredis error: %v", err)
+	}
+	defer srv.Close()
+
+	fmt.Printf("miniredis listening on %s, press Ctrl+C to stop\n", srv.Addr())
+	<-ctx.Done()
+}
+
+// loadgen 压测运行状态
+type loadgen struct {
+	opts    *options
+	fixture *fixture
+	clients map[int]*sdk.Client
+
+	connect recorder // 建立连接耗时
+	push    recorder // 端到端推送延迟
+	reply   recorder // 发送回执耗时
+
+	connectErrors atomic.Int64
+	disconnects   atomic.Int64
+	sent          atomic.Int64
+	sendErrors    atomic.Int64
+	received      atomic.Int64
+}
+
+func run(ctx context.Context, o *options, f *fixture) {
+	l := &loadgen{opts: o, fixture: f, clients: make(map[int]*sdk.Client, o.conns)}
+
+	begin := time.Now()
+	l.dialAll(ctx)
+	fmt.Printf("connected %d/%d in %s\n", len(l.clients), o.conns, time.Since(begin).Round(time.Millisecond))
+
+	defer func() {
+		for _, client := range l.clients {
+			_ = client.Close()
+		}
+	}()
+
+	if ctx.Err() == nil && o.senders > 0 && o.rate > 0 {
+		l.publish(ctx)
+
+		// 等待在途消息送达
+		time.Sleep(2 * time.Second)
+	}
+
+	l.report()
+}
+
+// dialAll 按 ramp 速率建立连接
+func (l *loadgen) dialAll(ctx context.Context) {
+	limiter := rate.NewLimiter(rate.Limit(l.opts.ramp), 1)
+
+	var (
+		mu sync.Mutex
+		wg sync.WaitGroup
+	)
+
+	for uid := l.opts.startUid; uid < l.opts.startUid+l.opts.conns; uid++ {
+		if err := limiter.Wait(ctx); err != nil {
+			break
+		}
+
+		wg.Add(1)
+		go func(uid int) {
+			defer wg.Done()
+
+			client := l.newClient(uid)
+
+			begin := time.Now()
+			if err := client.Connect(ctx); err != nil {
+				l.connectErrors.Add(1)
+				return
+			}
+			l.connect.add(time.Since(begin))
+
+			mu.Lock()
+			l.clients[uid] = client
+			mu.Unlock()
+		}(uid)
+	}
+
+	wg.Wait()
+}
+
+func (l *loadgen) newClient(uid int) *sdk.Client {
+	client := sdk.New(&sdk.Options{
+		Network: l.opts.network,
+		Addr:    l.opts.addr,
+		Token:   l.fixture.tokens[uid],
+	})
+
+	client.OnImMessage(func(msg *sdk.ImMessage) {
+		if sentAt, ok := sentTime(msg); ok {
+			l.received.Add(1)
+			l.push.add(time.Since(sentAt))
+		}
+	})
+
+	client.OnDisconnect(func(err error) {
+		l.disconnects.Add(1)
+	})
+
+	return client
+}
+
+// publish 由前 senders 个连接轮流按总速率发送消息
+func (l *loadgen) publish(ctx context.Context) {
+	senders := make([]int, 0, l.opts.senders)
+	for uid := l.opts.startUid; uid < l.opts.startUid+l.opts.conns && len(senders) < l.opts.senders; uid++ {
+		if _, ok := l.clients[uid]; ok {
+			senders = append(senders, uid)
+		}
+	}
+
+	if len(senders) == 0 {
+		return
+	}
+
+	// 发送截止后在途请求仍等待回执
+	deadline, cancel := context.WithTimeout(ctx, l.opts.duration)
+	defer cancel()
+
+	limiter := rate.NewLimiter(rate.Limit(l.opts.rate), 1)
+
+	ticker := time.NewTicker(5 * time.Second)
+	defer ticker.Stop()
+
+	var wg sync.WaitGroup
+	for index := 0; ; index++ {
+		if err := limiter.Wait(deadline); err != nil {
+			break
+		}
+
+		select {
+		case <-ticker.C:
+			fmt.Printf("sent=%d received=%d send_errors=%d disconnects=%d\n", l.sent.Load(), l.received.Load(), l.sendErrors.Load(), l.disconnects.Load())
+		default:
+		}
+
+		uid := senders[index%len(senders)]
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			l.send(ctx, uid)
+		}()
+	}
+
+	wg.Wait()
+}
+
+// send 发送一条携带发送时间的文本消息并等待回执
+func (l *loadgen) send(ctx context.Context, uid int) {
+	talkType, receiverId := constant.ChatPrivateMode, l.opts.startUid+(uid-l.opts.startUid+1)%l.opts.conns
+	if gid, ok := l.fixture.member[uid]; ok {
+		talkType, receiverId = constant.ChatGroupMode, gid
+	}
+
+	content := map[string]any{
+		"type":    constant.Text,
+		"content": contentPrefix + strconv.FormatInt(time.Now().UnixNano(), 10),
+		"receiver": map[string]int{
+			"talk_type":   talkType,
+			"receiver_id": receiverId,
+		},
+	}
+
+	begin := time.Now()
+	l.sent.Add(1)
+	if _, err := l.clients[uid].Call(ctx, sdk.EventImMessagePublish, content); err != nil {
+		l.sendErrors.Add(1)
+		return
+	}
+	l.reply.add(time.Since(begin))
+}
+
+// sentTime 解析消息内容中的发送时间
+func sentTime(msg *sdk.ImMessage) (time.Time, bool) {
+	if msg.Data == nil {
+		return time.Time{}, false
+	}
+
+	extra, ok := msg.Data.Extra.(map[string]any)
+	if !ok {
+		return time.Time{}, false
+	}
+
+	content, _ := extra["content"].(string)
+	value, ok := strings.CutPrefix(content, contentPrefix)
+	if !ok {
+		return time.Time{}, false
+	}
+
+	nano, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	return time.Unix(0, nano), true
+}
+
+func (l *loadgen) report() {
+	fmt.Println("---------------- report ----------------")
+	fmt.Printf("network:      %s %s\n", l.opts.network, l.opts.addr)
+	fmt.Printf("connections:  %d ok, %d failed, %d disconnects\n", len(l.clients), l.connectErrors.Load(), l.disconnects.Load())
+	fmt.Printf("connect:      %s\n", l.connect.summary())
+	fmt.Printf("messages:     %d sent, %d failed, %d received\n", l.sent.Load(), l.sendErrors.Load(), l.received.Load())
+	fmt.Printf("publish rtt:  %s\n", l.reply.summary())
+	fmt.Printf("push latency: %s\n", l.push.summary())
+}
+
+func exit(format string, args ...any) {
+	fmt.Fprintf(os.Stderr, format+"\n", args...)
+	os.Exit(1)
+}
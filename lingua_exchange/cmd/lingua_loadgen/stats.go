@@ -0,0 +1,49 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+)
+
+// recorder 耗时采样
+type recorder struct {
+	mu      sync.Mutex
+	samples []time.Duration
+}
+
+func (r *recorder) add(d time.Duration) {
+	r.mu.Lock()
+	r.samples = append(r.samples, d)
+	r.mu.Unlock()
+}
+
+// summary 返回采样数及 p50/p90/p99/max
+func (r *recorder) summary() string {
+	r.mu.Lock()
+	samples := slices.Clone(r.samples)
+	r.mu.Unlock()
+
+	if len(samples) == 0 {
+		return "count=0"
+	}
+
+	slices.Sort(samples)
+
+	return fmt.Sprintf("count=%d p50=%s p90=%s p99=%s max=%s",
+		len(samples),
+		percentile(samples, 0.50),
+		percentile(samples, 0.90),
+		percentile(samples, 0.99),
+		samples[len(samples)-1],
+	)
+}
+
+// percentile 已排序采样的分位值
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	index := int(float64(len(sorted))*p+0.5) - 1
+	index = max(0, min(index, len(sorted)-1))
+
+	return sorted[index].Round(10 * time.Microsecond)
+}
@@ -0,0 +1,116 @@
+package bus
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/sourcegraph/conc/pool"
+	"github.com/zhufuyi/sponge/pkg/logger"
+	"lingua_exchange/internal/config"
+	"lingua_exchange/internal/constant"
+	"lingua_exchange/internal/model"
+	"lingua_exchange/internal/types"
+	"lingua_exchange/pkg/jsonutil"
+)
+
+// 每个订阅的最大并发处理数
+const subscribeConcurrency = 10
+
+// Handler 订阅消息处理函数，返回 nil 表示处理成功，支持确认的传输方式会在成功后确认消息
+type Handler func(ctx context.Context, msg *types.SubscribeContent) error
+
+// Publisher 消息发布
+type Publisher interface {
+	Publish(ctx context.Context, topic string, msg *types.SubscribeContent) error
+}
+
+// Subscriber 消息订阅，阻塞直到 ctx 结束
+type Subscriber interface {
+	Subscribe(ctx context.Context, topics []string, handler Handler) error
+}
+
+// Purger 清理已下线节点在消息总线中遗留的订阅状态
+type Purger interface {
+	Purge(ctx context.Context, sid string) error
+}
+
+// Bus 消息总线
+type Bus interface {
+	Publisher
+	Subscriber
+	Close() error
+}
+
+var (
+	defaultBus Bus
+	once       sync.Once
+)
+
+// Get 获取 config.App.MessageTransport 指定的消息总线，进程内单例
+func Get() Bus {
+	once.Do(func() {
+		b, err := New(config.Get())
+		if err != nil {
+			panic("bus.New error: " + err.Error())
+		}
+		defaultBus = b
+	})
+
+	return defaultBus
+}
+
+// New 根据配置创建消息总线
+func New(conf *config.Config) (Bus, error) {
+	switch conf.App.MessageTransport {
+	case "", constant.ImTransportPubSub:
+		return NewRedisPubSub(model.GetRedisCli()), nil
+	case constant.ImTransportStream:
+		return NewRedisStream(model.GetRedisCli(), conf.App.Sid, conf.App.StreamMaxLen), nil
+	case constant.ImTransportNats:
+		return NewNats(conf.Nats.URL)
+	case constant.ImTransportMemory:
+		return NewMemory(), nil
+	default:
+		return nil, fmt.Errorf("unsupported message transport: %s", conf.App.MessageTransport)
+	}
+}
+
+// NewMessage 构建订阅消息
+func NewMessage(event string, data any) *types.SubscribeContent {
+	return &types.SubscribeContent{
+		Version: types.SubscribeContentVersion,
+		Event:   event,
+		Data:    jsonutil.Encode(data),
+	}
+}
+
+// encode 序列化消息，未设置版本时使用当前版本
+func encode(msg *types.SubscribeContent) []byte {
+	if msg.Version == 0 {
+		msg.Version = types.SubscribeContentVersion
+	}
+
+	return jsonutil.Marshal(msg)
+}
+
+// dispatch 解析消息并调用处理函数，无法解析或版本高于当前节点的消息视为已处理，避免反复投递
+func dispatch(ctx context.Context, handler Handler, payload []byte) error {
+	var msg types.SubscribeContent
+	if err := json.Unmarshal(payload, &msg); err != nil {
+		logger.Error("bus message unmarshal err", logger.Err(err))
+		return nil
+	}
+
+	if msg.Version > types.SubscribeContentVersion {
+		logger.Warn("bus message version not supported", logger.String("event", msg.Event), logger.Int("version", msg.Version))
+		return nil
+	}
+
+	return handler(ctx, &msg)
+}
+
+func newWorker() *pool.Pool {
+	return pool.New().WithMaxGoroutines(subscribeConcurrency)
+}
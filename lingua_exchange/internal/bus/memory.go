@@ -0,0 +1,92 @@
+package bus
+
+import (
+	"context"
+	"slices"
+	"sync"
+
+	"github.com/zhufuyi/sponge/pkg/logger"
+	"lingua_exchange/internal/types"
+)
+
+// 每个订阅的缓冲区大小
+const memoryBuffer = 1024
+
+// Memory 进程内消息总线，仅适用于单节点部署及测试
+type Memory struct {
+	mu   sync.RWMutex
+	subs map[string][]*memorySub // 只替换不修改，发布时可在锁外遍历
+}
+
+type memorySub struct {
+	ch   chan []byte
+	done chan struct{} // 取消订阅后关闭，避免发布方阻塞在已停止读取的订阅上
+}
+
+func NewMemory() *Memory {
+	return &Memory{subs: make(map[string][]*memorySub)}
+}
+
+// Publish 投递到当前进程内的所有订阅，订阅缓冲区满时阻塞直到 ctx 结束或取消订阅
+func (m *Memory) Publish(ctx context.Context, topic string, msg *types.SubscribeContent) error {
+	payload := encode(msg)
+
+	m.mu.RLock()
+	subs := m.subs[topic]
+	m.mu.RUnlock()
+
+	for _, sub := range subs {
+		select {
+		case sub.ch <- payload:
+		case <-sub.done:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	return nil
+}
+
+func (m *Memory) Subscribe(ctx context.Context, topics []string, handler Handler) error {
+	sub := &memorySub{ch: make(chan []byte, memoryBuffer), done: make(chan struct{})}
+
+	m.mu.Lock()
+	for _, topic := range topics {
+		m.subs[topic] = append(slices.Clip(m.subs[topic]), sub)
+	}
+	m.mu.Unlock()
+
+	defer m.unsubscribe(topics, sub)
+
+	worker := newWorker()
+	defer worker.Wait()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case payload := <-sub.ch:
+			worker.Go(func() {
+				if err := dispatch(ctx, handler, payload); err != nil {
+					logger.Error("bus memory handle err", logger.Err(err))
+				}
+			})
+		}
+	}
+}
+
+func (m *Memory) unsubscribe(topics []string, sub *memorySub) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	close(sub.done)
+	for _, topic := range topics {
+		m.subs[topic] = slices.DeleteFunc(slices.Clone(m.subs[topic]), func(item *memorySub) bool {
+			return item == sub
+		})
+	}
+}
+
+func (m *Memory) Close() error {
+	return nil
+}
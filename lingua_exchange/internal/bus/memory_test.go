@@ -0,0 +1,57 @@
+package bus
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"lingua_exchange/internal/types"
+)
+
+func TestMemory_Unsubscribe(t *testing.T) {
+	m := NewMemory()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	handled := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_ = m.Subscribe(ctx, []string{"topic"}, func(ctx context.Context, msg *types.SubscribeContent) error {
+			<-handled
+			return nil
+		})
+	}()
+
+	assert.Eventually(t, func() bool {
+		m.mu.RLock()
+		defer m.mu.RUnlock()
+		return len(m.subs["topic"]) == 1
+	}, time.Second, time.Millisecond)
+
+	// 订阅缓冲区写满后发布方阻塞，取消订阅不能被阻塞的发布方卡住
+	published := make(chan struct{})
+	go func() {
+		defer close(published)
+		for i := 0; i < memoryBuffer+subscribeConcurrency+2; i++ {
+			_ = m.Publish(context.Background(), "topic", NewMessage("event", i))
+		}
+	}()
+
+	time.Sleep(50 * time.Millisecond)
+	cancel()
+	close(handled)
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("unsubscribe blocked by publisher")
+	}
+
+	select {
+	case <-published:
+	case <-time.After(time.Second):
+		t.Fatal("publisher blocked after unsubscribe")
+	}
+	assert.Empty(t, m.subs["topic"])
+}
@@ -0,0 +1,70 @@
+package bus
+
+import (
+	"context"
+
+	"github.com/nats-io/nats.go"
+	"github.com/zhufuyi/sponge/pkg/logger"
+	"lingua_exchange/internal/types"
+)
+
+// Nats NATS 消息总线
+type Nats struct {
+	conn *nats.Conn
+}
+
+func NewNats(url string) (*Nats, error) {
+	if url == "" {
+		url = nats.DefaultURL
+	}
+
+	conn, err := nats.Connect(url, nats.MaxReconnects(-1))
+	if err != nil {
+		return nil, err
+	}
+
+	return &Nats{conn: conn}, nil
+}
+
+func (n *Nats) Publish(ctx context.Context, topic string, msg *types.SubscribeContent) error {
+	return n.conn.Publish(topic, encode(msg))
+}
+
+func (n *Nats) Subscribe(ctx context.Context, topics []string, handler Handler) error {
+	ch := make(chan *nats.Msg, 1024)
+
+	subs := make([]*nats.Subscription, 0, len(topics))
+	defer func() {
+		for _, sub := range subs {
+			_ = sub.Unsubscribe()
+		}
+	}()
+
+	for _, topic := range topics {
+		sub, err := n.conn.ChanSubscribe(topic, ch)
+		if err != nil {
+			return err
+		}
+		subs = append(subs, sub)
+	}
+
+	worker := newWorker()
+	defer worker.Wait()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case data := <-ch:
+			worker.Go(func() {
+				if err := dispatch(ctx, handler, data.Data); err != nil {
+					logger.Error("bus nats handle err", logger.String("topic", data.Subject), logger.Err(err))
+				}
+			})
+		}
+	}
+}
+
+func (n *Nats) Close() error {
+	return n.conn.Drain()
+}
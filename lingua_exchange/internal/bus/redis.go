@@ -0,0 +1,51 @@
+package bus
+
+import (
+	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/zhufuyi/sponge/pkg/logger"
+	"lingua_exchange/internal/types"
+)
+
+// RedisPubSub Redis Pub/Sub 消息总线，节点离线期间的消息会丢失
+type RedisPubSub struct {
+	redis *redis.Client
+}
+
+func NewRedisPubSub(rdb *redis.Client) *RedisPubSub {
+	return &RedisPubSub{redis: rdb}
+}
+
+func (r *RedisPubSub) Publish(ctx context.Context, topic string, msg *types.SubscribeContent) error {
+	return r.redis.Publish(ctx, topic, encode(msg)).Err()
+}
+
+func (r *RedisPubSub) Subscribe(ctx context.Context, topics []string, handler Handler) error {
+	sub := r.redis.Subscribe(ctx, topics...)
+
+	go func() {
+		<-ctx.Done()
+		_ = sub.Close()
+	}()
+
+	worker := newWorker()
+
+	for data := range sub.Channel(redis.WithChannelHealthCheckInterval(10 * time.Second)) {
+		payload := []byte(data.Payload)
+		worker.Go(func() {
+			if err := dispatch(ctx, handler, payload); err != nil {
+				logger.Error("bus pubsub handle err", logger.String("topic", data.Channel), logger.Err(err))
+			}
+		})
+	}
+
+	worker.Wait()
+
+	return nil
+}
+
+func (r *RedisPubSub) Close() error {
+	return nil
+}
@@ -0,0 +1,207 @@
+package bus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/sourcegraph/conc/pool"
+	"github.com/zhufuyi/sponge/pkg/logger"
+	"lingua_exchange/internal/constant"
+	"lingua_exchange/internal/types"
+	"lingua_exchange/pkg/strutil"
+)
+
+const (
+	streamPayload       = "payload"        // 消息内容字段
+	streamDefaultMaxLen = 100000           // 默认最大长度(近似裁剪)
+	streamReadCount     = 10               // 单次读取消息数量
+	streamReadBlock     = 5 * time.Second  // 读取阻塞时间
+	streamReclaimPeriod = 30 * time.Second // 待确认消息回收周期
+	streamReclaimIdle   = time.Minute      // 超过该空闲时间的待确认消息视为消费者已崩溃
+	streamMaxDelivery   = 5                // 最大投递次数，超过后直接确认丢弃
+	streamScanCount     = 100              // 清理时单次扫描的键数量
+)
+
+var _ Purger = (*RedisStream)(nil)
+
+// RedisStream Redis Streams 消息总线，每个节点一个消费组，处理成功后确认
+type RedisStream struct {
+	redis  *redis.Client
+	group  string
+	maxLen int64
+}
+
+// NewRedisStream group 为消费组名称，使用固定的节点ID，重启后从上次确认的位置继续消费
+func NewRedisStream(rdb *redis.Client, group string, maxLen int64) *RedisStream {
+	if maxLen <= 0 {
+		maxLen = streamDefaultMaxLen
+	}
+
+	return &RedisStream{redis: rdb, group: group, maxLen: maxLen}
+}
+
+func (r *RedisStream) Publish(ctx context.Context, topic string, msg *types.SubscribeContent) error {
+	return r.redis.XAdd(ctx, &redis.XAddArgs{
+		Stream: topic,
+		MaxLen: r.maxLen,
+		Approx: true,
+		Values: map[string]any{streamPayload: encode(msg)},
+	}).Err()
+}
+
+func (r *RedisStream) Subscribe(ctx context.Context, topics []string, handler Handler) error {
+	// 同一节点重启后使用新的消费者名称，由回收任务接管旧消费者未确认的消息
+	consumer := fmt.Sprintf("%s-%s", r.group, strutil.Random(6))
+
+	for _, stream := range topics {
+		err := r.redis.XGroupCreateMkStream(ctx, stream, r.group, "$").Err()
+		if err != nil && !strings.HasPrefix(err.Error(), "BUSYGROUP") {
+			return err
+		}
+	}
+
+	worker := newWorker()
+	defer worker.Wait()
+
+	go r.reclaim(ctx, worker, topics, consumer, handler)
+
+	args := make([]string, 0, len(topics)*2)
+	args = append(args, topics...)
+	for range topics {
+		args = append(args, ">")
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		default:
+		}
+
+		items, err := r.redis.XReadGroup(ctx, &redis.XReadGroupArgs{
+			Group:    r.group,
+			Consumer: consumer,
+			Streams:  args,
+			Count:    streamReadCount,
+			Block:    streamReadBlock,
+		}).Result()
+		if err != nil {
+			if !errors.Is(err, redis.Nil) && ctx.Err() == nil {
+				logger.Error("bus stream read err", logger.Err(err))
+				time.Sleep(time.Second)
+			}
+			continue
+		}
+
+		for _, item := range items {
+			for _, message := range item.Messages {
+				r.handle(ctx, worker, item.Stream, message, handler)
+			}
+		}
+	}
+}
+
+// reclaim 回收崩溃消费者长时间未确认的消息，超过最大投递次数的消息直接确认
+func (r *RedisStream) reclaim(ctx context.Context, worker *pool.Pool, streams []string, consumer string, handler Handler) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(streamReclaimPeriod):
+		}
+
+		for _, stream := range streams {
+			pending, err := r.redis.XPendingExt(ctx, &redis.XPendingExtArgs{
+				Stream: stream,
+				Group:  r.group,
+				Idle:   streamReclaimIdle,
+				Start:  "-",
+				End:    "+",
+				Count:  100,
+			}).Result()
+			if err != nil {
+				continue
+			}
+
+			ids := make([]string, 0, len(pending))
+			for _, item := range pending {
+				if item.RetryCount >= streamMaxDelivery {
+					logger.Warn("bus stream drop message", logger.String("stream", stream), logger.String("id", item.ID))
+					r.redis.XAck(ctx, stream, r.group, item.ID)
+					continue
+				}
+
+				ids = append(ids, item.ID)
+			}
+
+			if len(ids) == 0 {
+				continue
+			}
+
+			messages, err := r.redis.XClaim(ctx, &redis.XClaimArgs{
+				Stream:   stream,
+				Group:    r.group,
+				Consumer: consumer,
+				MinIdle:  streamReclaimIdle,
+				Messages: ids,
+			}).Result()
+			if err != nil {
+				continue
+			}
+
+			for _, message := range messages {
+				r.handle(ctx, worker, stream, message, handler)
+			}
+		}
+	}
+}
+
+func (r *RedisStream) handle(ctx context.Context, worker *pool.Pool, stream string, message redis.XMessage, handler Handler) {
+	worker.Go(func() {
+		payload, _ := message.Values[streamPayload].(string)
+
+		if err := dispatch(ctx, handler, []byte(payload)); err != nil {
+			logger.Error("bus stream handle err", logger.String("stream", stream), logger.String("id", message.ID), logger.Err(err))
+			return
+		}
+
+		// 处理成功的消息在停止订阅时也需要确认，避免被其它消费者重复处理
+		r.redis.XAck(context.WithoutCancel(ctx), stream, r.group, message.ID)
+	})
+}
+
+// Purge 删除节点在公共消息流中的消费组及其私有消息流
+func (r *RedisStream) Purge(ctx context.Context, sid string) error {
+	var cursor uint64
+
+	for {
+		keys, next, err := r.redis.ScanType(ctx, cursor, constant.ImTopicPattern, streamScanCount, "stream").Result()
+		if err != nil {
+			return err
+		}
+
+		for _, key := range keys {
+			if strings.HasSuffix(key, ":"+sid) {
+				err = r.redis.Del(ctx, key).Err()
+			} else {
+				err = r.redis.XGroupDestroy(ctx, key, sid).Err()
+			}
+
+			if err != nil && !errors.Is(err, redis.Nil) {
+				return err
+			}
+		}
+
+		if cursor = next; cursor == 0 {
+			return nil
+		}
+	}
+}
+
+func (r *RedisStream) Close() error {
+	return nil
+}
@@ -0,0 +1,37 @@
+package bus
+
+import (
+	"context"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedisStream_Purge(t *testing.T) {
+	mr := miniredis.RunT(t)
+	rds := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	defer rds.Close()
+
+	ctx := context.Background()
+	for _, stream := range []string{"im:message:chat:all", "im:message:chat:dead", "im:message:chat:alive", "im:message:live:all"} {
+		for _, group := range []string{"dead", "alive"} {
+			assert.NoError(t, rds.XGroupCreateMkStream(ctx, stream, group, "0").Err())
+		}
+	}
+	assert.NoError(t, rds.Set(ctx, "im:message:other:dead", "1", 0).Err())
+
+	assert.NoError(t, NewRedisStream(rds, "alive", 0).Purge(ctx, "dead"))
+
+	// 私有消息流整体删除，公共消息流只删除该节点的消费组
+	assert.False(t, mr.Exists("im:message:chat:dead"))
+	assert.True(t, mr.Exists("im:message:chat:alive"))
+	assert.True(t, mr.Exists("im:message:other:dead"))
+	for _, stream := range []string{"im:message:chat:all", "im:message:live:all"} {
+		groups, err := rds.XInfoGroups(ctx, stream).Result()
+		assert.NoError(t, err)
+		assert.Len(t, groups, 1)
+		assert.Equal(t, "alive", groups[0].Name)
+	}
+}
@@ -0,0 +1,168 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"lingua_exchange/internal/model"
+	"lingua_exchange/pkg/socket"
+)
+
+const (
+	ackCountKey  = "ws:ack:count" // 用户未确认消息数排行
+	ackExpiresIn = 7 * 24 * time.Hour
+)
+
+var _ AckCache = (*ackCache)(nil)
+
+// AckCache 未确认消息缓存，按用户、渠道及设备保存已推送但客户端未确认的消息
+type AckCache interface {
+	Save(ctx context.Context, scope *socket.AckScope, sid string, data []byte) error
+	Delete(ctx context.Context, scope *socket.AckScope, sid string) error
+	All(ctx context.Context, scope *socket.AckScope) ([]*socket.AckMessage, error)
+	Count(ctx context.Context, uid int) int64
+	Users(ctx context.Context, min int64, limit int64) (map[int]int64, error)
+}
+
+// 用户未确认消息数取各渠道、设备中积压最多的一份，同一条消息推送到多个设备时不重复计数
+const ackRankScript = `
+local max = 0
+for _, v in ipairs(redis.call("HVALS", KEYS[3])) do
+	if tonumber(v) > max then
+		max = tonumber(v)
+	end
+end
+if max > 0 then
+	redis.call("ZADD", KEYS[4], max, ARGV[1])
+else
+	redis.call("ZREM", KEYS[4], ARGV[1])
+end
+`
+
+// 新增记录时按发送时间排序，并同步更新用户未确认消息数
+// KEYS: 消息内容、发送顺序、用户各设备未确认数、未确认数排行
+// ARGV: uid、sid、data、发送时间、设备、过期时间
+var ackSaveScript = redis.NewScript(`
+if redis.call("HSETNX", KEYS[1], ARGV[2], ARGV[3]) == 1 then
+	redis.call("ZADD", KEYS[2], ARGV[4], ARGV[2])
+	redis.call("HINCRBY", KEYS[3], ARGV[5], 1)
+` + ackRankScript + `
+end
+redis.call("EXPIRE", KEYS[1], ARGV[6])
+redis.call("EXPIRE", KEYS[2], ARGV[6])
+redis.call("EXPIRE", KEYS[3], ARGV[6])
+return 1
+`)
+
+// 删除记录时同步更新用户未确认消息数
+// KEYS: 同 ackSaveScript
+// ARGV: uid、sid、设备
+var ackDeleteScript = redis.NewScript(`
+if redis.call("HDEL", KEYS[1], ARGV[2]) == 1 then
+	redis.call("ZREM", KEYS[2], ARGV[2])
+	if tonumber(redis.call("HINCRBY", KEYS[3], ARGV[3], -1)) <= 0 then
+		redis.call("HDEL", KEYS[3], ARGV[3])
+	end
+` + ackRankScript + `
+end
+return 1
+`)
+
+type ackCache struct {
+	redis *redis.Client
+}
+
+func NewAckCache() AckCache {
+	return &ackCache{redis: model.GetRedisCli()}
+}
+
+// Save 保存未确认消息
+// @params scope 消息归属
+// @params sid   消息 ACK ID
+// @params data  推送内容
+func (a *ackCache) Save(ctx context.Context, scope *socket.AckScope, sid string, data []byte) error {
+	return ackSaveScript.Run(ctx, a.redis, a.keys(scope), scope.Uid, sid, data, time.Now().UnixMicro(), a.device(scope), int(ackExpiresIn.Seconds())).Err()
+}
+
+// Delete 客户端确认后删除消息
+// @params scope 消息归属
+// @params sid   消息 ACK ID
+func (a *ackCache) Delete(ctx context.Context, scope *socket.AckScope, sid string) error {
+	return ackDeleteScript.Run(ctx, a.redis, a.keys(scope), scope.Uid, sid, a.device(scope)).Err()
+}
+
+// All 按发送顺序获取未确认消息
+// @params scope 消息归属
+func (a *ackCache) All(ctx context.Context, scope *socket.AckScope) ([]*socket.AckMessage, error) {
+	keys := a.keys(scope)
+
+	sids, err := a.redis.ZRange(ctx, keys[1], 0, -1).Result()
+	if err != nil || len(sids) == 0 {
+		return nil, err
+	}
+
+	values, err := a.redis.HMGet(ctx, keys[0], sids...).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	items := make([]*socket.AckMessage, 0, len(sids))
+	for i, sid := range sids {
+		if value, ok := values[i].(string); ok {
+			items = append(items, &socket.AckMessage{Sid: sid, Data: []byte(value)})
+		}
+	}
+
+	return items, nil
+}
+
+// Count 获取用户未确认(未送达)消息数，取各渠道、设备中最多的一份
+// @params uid 用户ID
+func (a *ackCache) Count(ctx context.Context, uid int) int64 {
+	val, _ := a.redis.ZScore(ctx, ackCountKey, strconv.Itoa(uid)).Result()
+	return int64(val)
+}
+
+// Users 获取未确认消息数不少于 min 的用户，用于触发离线推送
+// @params min   最少未确认消息数
+// @params limit 返回的用户数
+func (a *ackCache) Users(ctx context.Context, min int64, limit int64) (map[int]int64, error) {
+	items, err := a.redis.ZRevRangeByScoreWithScores(ctx, ackCountKey, &redis.ZRangeBy{
+		Min:   strconv.FormatInt(min, 10),
+		Max:   "+inf",
+		Count: limit,
+	}).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	users := make(map[int]int64, len(items))
+	for _, item := range items {
+		member, _ := item.Member.(string)
+		if uid, err := strconv.Atoi(member); err == nil {
+			users[uid] = int64(item.Score)
+		}
+	}
+
+	return users, nil
+}
+
+// keys 消息内容、发送顺序、用户各设备未确认数及未确认数排行的缓存键
+func (a *ackCache) keys(scope *socket.AckScope) []string {
+	name := fmt.Sprintf("%s:%d:%s", scope.Channel, scope.Uid, scope.Device)
+
+	return []string{
+		"ws:ack:msg:" + name,
+		"ws:ack:seq:" + name,
+		fmt.Sprintf("ws:ack:devices:%d", scope.Uid),
+		ackCountKey,
+	}
+}
+
+// device 用户未确认数中的渠道及设备字段
+func (a *ackCache) device(scope *socket.AckScope) string {
+	return scope.Channel + ":" + scope.Device
+}
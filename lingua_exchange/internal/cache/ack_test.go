@@ -0,0 +1,77 @@
+package cache
+
+import (
+	"context"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"lingua_exchange/pkg/socket"
+)
+
+func TestAckCache_Scope(t *testing.T) {
+	mr := miniredis.RunT(t)
+	rds := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	defer rds.Close()
+
+	ctx := context.Background()
+	a := &ackCache{redis: rds}
+
+	phone := &socket.AckScope{Uid: 7, Channel: "chat", Device: "phone"}
+	pc := &socket.AckScope{Uid: 7, Channel: "chat", Device: "pc"}
+	notice := &socket.AckScope{Uid: 7, Channel: "notice", Device: "phone"}
+
+	sids := func(scope *socket.AckScope) []string {
+		items, err := a.All(ctx, scope)
+		assert.NoError(t, err)
+
+		values := make([]string, 0, len(items))
+		for _, item := range items {
+			values = append(values, item.Sid+"="+string(item.Data))
+		}
+		return values
+	}
+
+	// 同一条消息推送到两台设备，各自保存一份
+	for _, sid := range []string{"p3", "p1", "p2"} {
+		assert.NoError(t, a.Save(ctx, phone, sid, []byte(sid)))
+	}
+	assert.NoError(t, a.Save(ctx, pc, "c1", []byte("c1")))
+	assert.NoError(t, a.Save(ctx, notice, "n1", []byte("n1")))
+
+	// 重复保存不重复计数
+	assert.NoError(t, a.Save(ctx, phone, "p1", []byte("p1")))
+
+	// 只返回本设备本渠道的消息，并保持发送顺序
+	assert.Equal(t, []string{"p3=p3", "p1=p1", "p2=p2"}, sids(phone))
+	assert.Equal(t, []string{"c1=c1"}, sids(pc))
+	assert.Equal(t, []string{"n1=n1"}, sids(notice))
+	assert.Empty(t, sids(&socket.AckScope{Uid: 7, Channel: "chat", Device: "pad"}))
+
+	// 未确认数取积压最多的设备
+	assert.Equal(t, int64(3), a.Count(ctx, 7))
+
+	assert.NoError(t, a.Delete(ctx, phone, "p1"))
+	assert.NoError(t, a.Delete(ctx, phone, "p1"))
+	assert.NoError(t, a.Delete(ctx, phone, "c1")) // 其它设备的 ACK ID 不受影响
+	assert.Equal(t, []string{"p3=p3", "p2=p2"}, sids(phone))
+	assert.Equal(t, []string{"c1=c1"}, sids(pc))
+	assert.Equal(t, int64(2), a.Count(ctx, 7))
+
+	users, err := a.Users(ctx, 2, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, map[int]int64{7: 2}, users)
+
+	// 全部确认后移出排行
+	for scope, sid := range map[*socket.AckScope][]string{phone: {"p2", "p3"}, pc: {"c1"}, notice: {"n1"}} {
+		for _, s := range sid {
+			assert.NoError(t, a.Delete(ctx, scope, s))
+		}
+	}
+	assert.Equal(t, int64(0), a.Count(ctx, 7))
+
+	users, err = a.Users(ctx, 1, 10)
+	assert.NoError(t, err)
+	assert.Empty(t, users)
+}
@@ -3,6 +3,8 @@ package cache
 import (
 	"context"
 	"fmt"
+	"slices"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -12,6 +14,7 @@ import (
 	"lingua_exchange/internal/config"
 	"lingua_exchange/internal/model"
 	"lingua_exchange/pkg/jsonutil"
+	"lingua_exchange/pkg/socket"
 )
 
 const lastMessageCacheKey = "redis:hash:last-message"
@@ -25,6 +28,14 @@ type MessageCache struct {
 
 type MessageModel struct {
 }
+
+// UserSession 用户在集群中的连接
+type UserSession struct {
+	Sid     string `json:"sid"`
+	Channel string `json:"channel"`
+	Cid     int64  `json:"cid"`
+	*socket.ClientMeta
+}
 type LastCacheMessage struct {
 	Content  string `json:"content"`
 	Datetime string `json:"datetime"`
@@ -105,6 +116,7 @@ func (m *MessageCache) Del(ctx context.Context, channel, fd string) error {
 	uid, _ := m.redisClient.HGet(ctx, key, fd).Result()
 	_, err := m.redisClient.Pipelined(ctx, func(pipe redis.Pipeliner) error {
 		pipe.HDel(ctx, key, fd)
+		pipe.HDel(ctx, m.metaKey(sid, channel), fd)
 		pipe.SRem(ctx, m.userKey(sid, channel, uid), fd)
 		return nil
 	})
@@ -167,8 +179,86 @@ func (m *MessageCache) GetClientIdFromUid(ctx context.Context, sid, channel, cid
 	return strconv.ParseInt(uid, 10, 64)
 }
 
-func (m *MessageCache) Bind(ctx context.Context, channel string, clientId int64, uid int) error {
-	return m.Set(ctx, channel, strconv.FormatInt(clientId, 10), uid)
+// GetClientMeta 获取节点客户端连接信息，未记录的客户端不在返回结果中
+// @params sid      服务ID
+// @params channel  渠道分组
+// @params cids     客户端ID
+func (m *MessageCache) GetClientMeta(ctx context.Context, sid, channel string, cids []int64) (map[int64]*socket.ClientMeta, error) {
+	items := make(map[int64]*socket.ClientMeta, len(cids))
+	if len(cids) == 0 {
+		return items, nil
+	}
+
+	fields := make([]string, 0, len(cids))
+	for _, cid := range cids {
+		fields = append(fields, strconv.FormatInt(cid, 10))
+	}
+
+	values, err := m.redisClient.HMGet(ctx, m.metaKey(sid, channel), fields...).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	for i, value := range values {
+		val, ok := value.(string)
+		if !ok {
+			continue
+		}
+
+		meta := &socket.ClientMeta{}
+		if err := jsonutil.Decode(val, meta); err != nil {
+			return nil, err
+		}
+
+		items[cids[i]] = meta
+	}
+
+	return items, nil
+}
+
+// UserSessions 获取用户在集群各运行节点指定渠道的连接，未记录连接信息时 ClientMeta 为空值
+// @params channel  渠道分组
+// @params uid      用户ID
+func (m *MessageCache) UserSessions(ctx context.Context, channel string, uid int) ([]*UserSession, error) {
+	servers := m.storage.All(ctx, 1)
+	sort.Strings(servers)
+
+	sessions := make([]*UserSession, 0)
+	for _, sid := range servers {
+		cids := m.GetUidFromClientIds(ctx, sid, channel, strconv.Itoa(uid))
+		if len(cids) == 0 {
+			continue
+		}
+		slices.Sort(cids)
+
+		metas, err := m.GetClientMeta(ctx, sid, channel, cids)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, cid := range cids {
+			meta, ok := metas[cid]
+			if !ok {
+				meta = &socket.ClientMeta{}
+			}
+
+			sessions = append(sessions, &UserSession{Sid: sid, Channel: channel, Cid: cid, ClientMeta: meta})
+		}
+	}
+
+	return sessions, nil
+}
+
+func (m *MessageCache) Bind(ctx context.Context, channel string, clientId int64, uid int, meta *socket.ClientMeta) error {
+	if err := m.Set(ctx, channel, strconv.FormatInt(clientId, 10), uid); err != nil {
+		return err
+	}
+
+	if meta == nil {
+		return nil
+	}
+
+	return m.redisClient.HSet(ctx, m.metaKey(m.config.App.Sid, channel), strconv.FormatInt(clientId, 10), jsonutil.Encode(meta)).Err()
 }
 
 func (m *MessageCache) UnBind(ctx context.Context, channel string, clientId int64) error {
@@ -179,6 +269,10 @@ func (m *MessageCache) clientKey(sid, channel string) string {
 	return fmt.Sprintf("ws:%s:%s:client", sid, channel)
 }
 
+func (m *MessageCache) metaKey(sid, channel string) string {
+	return fmt.Sprintf("ws:%s:%s:meta", sid, channel)
+}
+
 func (m *MessageCache) userKey(sid, channel, uid string) string {
 	return fmt.Sprintf("ws:%s:%s:user:%s", sid, channel, uid)
 }
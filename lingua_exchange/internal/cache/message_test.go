@@ -0,0 +1,68 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"lingua_exchange/internal/config"
+	"lingua_exchange/pkg/socket"
+)
+
+func TestMessageCache_ClientMeta(t *testing.T) {
+	mr := miniredis.RunT(t)
+	rds := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	defer rds.Close()
+
+	ctx := context.Background()
+	m := &MessageCache{redisClient: rds, config: &config.Config{App: config.App{Sid: "node-1"}}}
+
+	meta := &socket.ClientMeta{Network: "websocket", RemoteAddr: "10.0.0.1", ConnectedAt: 100}
+	assert.NoError(t, m.Bind(ctx, "chat", 1, 7, meta))
+	assert.NoError(t, m.Bind(ctx, "chat", 2, 7, nil))
+
+	assert.ElementsMatch(t, []int64{1, 2}, m.GetUidFromClientIds(ctx, "node-1", "chat", "7"))
+
+	items, err := m.GetClientMeta(ctx, "node-1", "chat", []int64{1, 2})
+	assert.NoError(t, err)
+	assert.Equal(t, map[int64]*socket.ClientMeta{1: meta}, items)
+
+	assert.NoError(t, m.UnBind(ctx, "chat", 1))
+	items, err = m.GetClientMeta(ctx, "node-1", "chat", []int64{1})
+	assert.NoError(t, err)
+	assert.Empty(t, items)
+	assert.Equal(t, []int64{2}, m.GetUidFromClientIds(ctx, "node-1", "chat", "7"))
+}
+
+func TestMessageCache_UserSessions(t *testing.T) {
+	mr := miniredis.RunT(t)
+	rds := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	defer rds.Close()
+
+	ctx := context.Background()
+	servers := &serverCache{redis: rds}
+	assert.NoError(t, servers.Set(ctx, "node-1", time.Now().Unix()))
+	assert.NoError(t, servers.Set(ctx, "node-2", time.Now().Unix()))
+	assert.NoError(t, servers.Set(ctx, "node-3", time.Now().Unix()-ServerOverTime))
+
+	node := func(sid string) *MessageCache {
+		return &MessageCache{redisClient: rds, config: &config.Config{App: config.App{Sid: sid}}, storage: servers}
+	}
+
+	ios := &socket.ClientMeta{Network: "websocket", Platform: "ios", ConnectedAt: 100}
+	assert.NoError(t, node("node-1").Bind(ctx, "chat", 1, 7, ios))
+	assert.NoError(t, node("node-2").Bind(ctx, "chat", 2, 7, nil))
+	assert.NoError(t, node("node-2").Bind(ctx, "chat", 3, 8, ios))
+	assert.NoError(t, node("node-3").Bind(ctx, "chat", 4, 7, ios))
+
+	// 仅返回运行中节点的连接
+	sessions, err := node("node-1").UserSessions(ctx, "chat", 7)
+	assert.NoError(t, err)
+	assert.Equal(t, []*UserSession{
+		{Sid: "node-1", Channel: "chat", Cid: 1, ClientMeta: ios},
+		{Sid: "node-2", Channel: "chat", Cid: 2, ClientMeta: &socket.ClientMeta{}},
+	}, sessions)
+}
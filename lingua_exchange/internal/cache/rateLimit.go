@@ -0,0 +1,65 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+var _ RateLimitCache = (*rateLimitCache)(nil)
+
+// RateLimitCache 用户级令牌桶限流，多节点共享
+type RateLimitCache interface {
+	Allow(ctx context.Context, uid int, event string, rate float64, burst int) (bool, error)
+}
+
+// 按时间补充令牌后尝试取出一个令牌
+var rateLimitScript = redis.NewScript(`
+local rate, burst, now = tonumber(ARGV[1]), tonumber(ARGV[2]), tonumber(ARGV[3])
+local data = redis.call("HMGET", KEYS[1], "tokens", "ts")
+local tokens = tonumber(data[1]) or burst
+local ts = tonumber(data[2]) or now
+tokens = math.min(burst, tokens + math.max(0, now - ts) * rate / 1000)
+local allowed = 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+end
+redis.call("HSET", KEYS[1], "tokens", tostring(tokens), "ts", now)
+redis.call("EXPIRE", KEYS[1], ARGV[4])
+return allowed
+`)
+
+type rateLimitCache struct {
+	redis *redis.Client
+}
+
+func NewRateLimitCache(rds *redis.Client) RateLimitCache {
+	return &rateLimitCache{redis: rds}
+}
+
+// Allow 校验用户事件是否允许处理
+// @params uid   用户ID
+// @params event 事件名，未配置规则的事件共用 *
+// @params rate  每秒生成的令牌数
+// @params burst 桶容量
+func (r *rateLimitCache) Allow(ctx context.Context, uid int, event string, rate float64, burst int) (bool, error) {
+	burst = max(burst, 1)
+
+	// 桶填满后的记录无需保留
+	ttl := int(math.Ceil(float64(burst)/rate)) + 1
+
+	val, err := rateLimitScript.Run(ctx, r.redis, []string{r.name(uid, event)}, rate, burst, time.Now().UnixMilli(), ttl).Int()
+	if err != nil {
+		return false, err
+	}
+
+	return val == 1, nil
+}
+
+func (r *rateLimitCache) name(uid int, event string) string {
+	return fmt.Sprintf("ws:rate:%d:%s", uid, event)
+}
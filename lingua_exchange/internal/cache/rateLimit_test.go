@@ -0,0 +1,42 @@
+package cache
+
+import (
+	"context"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_rateLimitCache_Allow(t *testing.T) {
+	mr := miniredis.RunT(t)
+	rds := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	defer rds.Close()
+
+	ctx := context.Background()
+
+	// 多个节点共享同一个令牌桶
+	nodes := []RateLimitCache{NewRateLimitCache(rds), NewRateLimitCache(rds)}
+
+	allowed := 0
+	for i := 0; i < 10; i++ {
+		ok, err := nodes[i%2].Allow(ctx, 1, "im.message.keyboard", 0.1, 3)
+		assert.NoError(t, err)
+		if ok {
+			allowed++
+		}
+	}
+	assert.Equal(t, 3, allowed)
+
+	// 不同用户及事件互不影响
+	ok, err := nodes[0].Allow(ctx, 2, "im.message.keyboard", 0.1, 3)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	ok, err = nodes[0].Allow(ctx, 1, "ping", 0.1, 3)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	assert.Greater(t, mr.TTL("ws:rate:1:im.message.keyboard").Seconds(), float64(0))
+}
@@ -2,6 +2,7 @@ package cache
 
 import (
 	"context"
+	"errors"
 	"strconv"
 	"strings"
 	"time"
@@ -10,6 +11,7 @@ import (
 	"github.com/zhufuyi/sponge/pkg/cache"
 	"github.com/zhufuyi/sponge/pkg/encoding"
 	"lingua_exchange/internal/model"
+	"lingua_exchange/pkg/jsonutil"
 )
 
 // ServerCache cache interface
@@ -20,6 +22,25 @@ type ServerCache interface {
 	All(ctx context.Context, status int) []string
 	SetExpireServer(ctx context.Context, server string) error
 	DelExpireServer(ctx context.Context, server string) error
+	LastHeartbeat(ctx context.Context, server string) (int64, error)
+	ScanServerKeys(ctx context.Context, server string, cursor uint64, count int64) ([]string, uint64, error)
+	DelKeys(ctx context.Context, keys []string) (int64, error)
+	SetStats(ctx context.Context, server string, stats *ServerStats) error
+	GetStats(ctx context.Context, servers []string) (map[string]*ServerStats, error)
+}
+
+// ServerStats 服务运行状态
+type ServerStats struct {
+	StartedAt  int64           `json:"started_at"`  // 启动时间
+	ReportedAt int64           `json:"reported_at"` // 上报时间
+	Channels   []*ChannelStats `json:"channels"`    // 各渠道连接状态
+}
+
+// ChannelStats 渠道连接状态
+type ChannelStats struct {
+	Name        string `json:"name"`         // 渠道名称
+	Connections int64  `json:"connections"`  // 连接数
+	Pending     int    `json:"out_chan_len"` // 发送通道待消费消息数
 }
 
 type ServerModel struct {
@@ -41,9 +62,14 @@ func (s *serverCache) Set(ctx context.Context, server string, time int64) error
 	return err
 }
 
-// Del 删除指定 ServerStorage
+// Del 删除指定 ServerStorage 及其运行状态
 func (s *serverCache) Del(ctx context.Context, server string) error {
-	return s.redis.HDel(ctx, ServerKey, server).Err()
+	_, err := s.redis.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HDel(ctx, ServerKey, server)
+		pipe.HDel(ctx, ServerKeyStats, server)
+		return nil
+	})
+	return err
 }
 
 // All 获取指定状态的运行 ServerStorage
@@ -95,6 +121,77 @@ func (s *serverCache) GetExpireServerAll(ctx context.Context) []string {
 	return s.redis.SMembers(ctx, ServerKeyExpire).Val()
 }
 
+// LastHeartbeat 服务最后一次心跳时间，未上报过时返回 0
+func (s *serverCache) LastHeartbeat(ctx context.Context, server string) (int64, error) {
+	value, err := s.redis.HGet(ctx, ServerKey, server).Int64()
+	if errors.Is(err, redis.Nil) {
+		return 0, nil
+	}
+
+	return value, err
+}
+
+// ScanServerKeys 分批扫描服务写入的连接状态键 ws:{sid}:*，cursor 为 0 表示扫描结束
+func (s *serverCache) ScanServerKeys(ctx context.Context, server string, cursor uint64, count int64) ([]string, uint64, error) {
+	return s.redis.Scan(ctx, cursor, serverKeyPattern(server), count).Result()
+}
+
+// DelKeys 异步删除指定键
+func (s *serverCache) DelKeys(ctx context.Context, keys []string) (int64, error) {
+	if len(keys) == 0 {
+		return 0, nil
+	}
+
+	return s.redis.Unlink(ctx, keys...).Result()
+}
+
+// SetStats 上报服务运行状态
+func (s *serverCache) SetStats(ctx context.Context, server string, stats *ServerStats) error {
+	return s.redis.HSet(ctx, ServerKeyStats, server, jsonutil.Encode(stats)).Err()
+}
+
+// GetStats 批量获取服务运行状态，未上报的服务不在返回结果中
+func (s *serverCache) GetStats(ctx context.Context, servers []string) (map[string]*ServerStats, error) {
+	items := make(map[string]*ServerStats, len(servers))
+	if len(servers) == 0 {
+		return items, nil
+	}
+
+	values, err := s.redis.HMGet(ctx, ServerKeyStats, servers...).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	for i, value := range values {
+		val, ok := value.(string)
+		if !ok {
+			continue
+		}
+
+		stats := &ServerStats{}
+		if err := jsonutil.Decode(val, stats); err != nil {
+			return nil, err
+		}
+
+		items[servers[i]] = stats
+	}
+
+	return items, nil
+}
+
+// serverKeyPattern 服务连接状态键的匹配模式，需转义服务ID中的通配符
+func serverKeyPattern(server string) string {
+	var b strings.Builder
+	for _, r := range server {
+		if strings.ContainsRune(`*?[]\`, r) {
+			b.WriteByte('\\')
+		}
+		b.WriteRune(r)
+	}
+
+	return "ws:" + b.String() + ":*"
+}
+
 func NewServerCache(cacheType *model.CacheType) ServerCache {
 	jsonEncoding := encoding.JSONEncoding{}
 	cachePrefix := ""
@@ -0,0 +1,76 @@
+package cache
+
+import (
+	"context"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_serverCache_ScanServerKeys(t *testing.T) {
+	mr := miniredis.RunT(t)
+	rds := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	defer rds.Close()
+
+	ctx := context.Background()
+	s := &serverCache{redis: rds}
+
+	last, err := s.LastHeartbeat(ctx, "dead")
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), last)
+
+	now := time.Now().Unix()
+	assert.NoError(t, s.Set(ctx, "dead", now))
+	last, err = s.LastHeartbeat(ctx, "dead")
+	assert.NoError(t, err)
+	assert.Equal(t, now, last)
+
+	rds.HSet(ctx, "ws:dead:chat:client", "1", 1)
+	rds.SAdd(ctx, "ws:dead:chat:user:1", 1)
+	rds.SAdd(ctx, "ws:dead:room_chat_group:1", 1)
+	rds.HSet(ctx, "ws:alive:chat:client", "2", 2)
+	rds.HSet(ctx, "ws:dead*:chat:client", "3", 3)
+
+	keys, cursor, err := s.ScanServerKeys(ctx, "dead", 0, 100)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(0), cursor)
+	sort.Strings(keys)
+	assert.Equal(t, []string{"ws:dead:chat:client", "ws:dead:chat:user:1", "ws:dead:room_chat_group:1"}, keys)
+
+	// 服务ID中的通配符按字面匹配
+	keys, _, err = s.ScanServerKeys(ctx, "dead*", 0, 100)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"ws:dead*:chat:client"}, keys)
+
+	n, err := s.DelKeys(ctx, []string{"ws:dead:chat:client", "ws:dead:chat:user:1", "ws:dead:room_chat_group:1"})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), n)
+	assert.True(t, mr.Exists("ws:alive:chat:client"))
+}
+
+func Test_serverCache_Stats(t *testing.T) {
+	mr := miniredis.RunT(t)
+	rds := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	defer rds.Close()
+
+	ctx := context.Background()
+	s := &serverCache{redis: rds}
+
+	stats := &ServerStats{StartedAt: 100, ReportedAt: 200, Channels: []*ChannelStats{{Name: "chat", Connections: 3, Pending: 1}}}
+	assert.NoError(t, s.Set(ctx, "node-1", 200))
+	assert.NoError(t, s.SetStats(ctx, "node-1", stats))
+
+	items, err := s.GetStats(ctx, []string{"node-1", "node-2"})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]*ServerStats{"node-1": stats}, items)
+
+	// 删除节点时一并删除运行状态
+	assert.NoError(t, s.Del(ctx, "node-1"))
+	items, err = s.GetStats(ctx, []string{"node-1"})
+	assert.NoError(t, err)
+	assert.Empty(t, items)
+}
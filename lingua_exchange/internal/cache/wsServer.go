@@ -19,6 +19,9 @@ const (
 	// ServerKeyExpire 过期的运行服务
 	ServerKeyExpire = "server_ids_expire"
 
+	// ServerKeyStats 运行服务的连接状态，随心跳上报
+	ServerKeyStats = "server_ids_stats"
+
 	// ServerOverTime 运行检测超时时间（单位秒）
 	ServerOverTime = 50
 )
@@ -0,0 +1,59 @@
+package consume
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"lingua_exchange/internal/constant"
+	"lingua_exchange/internal/types"
+	"lingua_exchange/pkg/socket"
+)
+
+// ChannelHandler 自定义渠道订阅消息消费者，将推送消息转发给渠道内的客户端
+type ChannelHandler struct {
+	channel socket.IChannel
+
+	once     sync.Once
+	handlers map[string]ConsumeHandler
+}
+
+func NewChannelHandler(channel socket.IChannel) *ChannelHandler {
+	return &ChannelHandler{channel: channel}
+}
+
+func (h *ChannelHandler) init() {
+	h.handlers = make(map[string]ConsumeHandler)
+
+	h.handlers[constant.SubEventChannelPush] = h.onConsumePush
+}
+
+// Call 分发订阅事件
+func (h *ChannelHandler) Call(ctx context.Context, event string, data []byte) error {
+	h.once.Do(h.init)
+
+	return dispatch(ctx, h.channel.Name(), h.handlers, event, data)
+}
+
+// 渠道消息推送
+func (h *ChannelHandler) onConsumePush(_ context.Context, body []byte) error {
+	var in types.ConsumeChannelPush
+	if err := json.Unmarshal(body, &in); err != nil {
+		return fmt.Errorf("[ChannelSubscribe] onConsumePush Unmarshal err: %w", err)
+	}
+
+	if in.Event == "" {
+		return fmt.Errorf("[ChannelSubscribe] onConsumePush event is empty")
+	}
+
+	c := socket.NewSenderContent()
+	c.SetAck(in.Ack)
+	c.SetBroadcast(in.Broadcast)
+	c.SetReceiveUsers(in.Uids...)
+	c.SetMessage(in.Event, in.Content)
+
+	h.channel.Write(c)
+
+	return nil
+}
@@ -2,7 +2,8 @@ package consume
 
 import (
 	"context"
-	"log"
+	"fmt"
+	"sync"
 
 	"github.com/redis/go-redis/v9"
 	"gorm.io/gorm"
@@ -11,53 +12,79 @@ import (
 	"lingua_exchange/internal/constant"
 	"lingua_exchange/internal/dao"
 	"lingua_exchange/internal/model"
+	"lingua_exchange/pkg/utils"
 )
 
-var handlers map[string]func(ctx context.Context, data []byte)
+// ConsumeHandler 订阅事件处理函数
+type ConsumeHandler func(ctx context.Context, data []byte) error
 
+// IMHandler 默认渠道订阅消息消费者
 type IMHandler struct {
 	chatRoom         cache.ChatRoomCache
-	messageCache     *cache.MessageCache
 	talkRecordsCache cache.TalkRecordsCache
 	talkRecordsDao   dao.TalkRecordsDao
 	redis            *redis.Client
 	db               *gorm.DB
 	config           *config.Config
+
+	once     sync.Once
+	handlers map[string]ConsumeHandler
 }
 
-func NewIMHandler() *IMHandler {
+func NewIMHandler(db *gorm.DB, rdb *redis.Client, conf *config.Config, cacheType *model.CacheType) *IMHandler {
 	return &IMHandler{
-		chatRoom:         cache.NewChatRoomCache(model.GetCacheType()),
-		messageCache:     cache.NewMessageCache(model.GetCacheType()),
-		talkRecordsCache: cache.NewTalkRecordsCache(model.GetCacheType()),
-		talkRecordsDao:   dao.NewTalkRecordsDao(model.GetDB(), cache.NewTalkRecordsCache(model.GetCacheType())),
-		redis:            model.GetRedisCli(),
-		db:               model.GetDB(),
-		config:           config.Get(),
+		chatRoom:         cache.NewChatRoomCache(cacheType),
+		talkRecordsCache: cache.NewTalkRecordsCache(cacheType),
+		talkRecordsDao:   dao.NewTalkRecordsDao(db, cache.NewTalkRecordsCache(cacheType)),
+		redis:            rdb,
+		db:               db,
+		config:           conf,
 	}
 }
 
 func (h *IMHandler) init() {
-	handlers = make(map[string]func(ctx context.Context, data []byte))
-
-	handlers[constant.SubEventImMessage] = h.onConsumeTalk
-	handlers[constant.SubEventImMessageKeyboard] = h.onConsumeTalkKeyboard
-	handlers[constant.SubEventImMessageRead] = h.onConsumeTalkRead
-	handlers[constant.SubEventImMessageRevoke] = h.onConsumeTalkRevoke
-	handlers[constant.SubEventContactStatus] = h.onConsumeContactStatus
-	handlers[constant.SubEventContactApply] = h.onConsumeContactApply
-	handlers[constant.SubEventGroupJoin] = h.onConsumeGroupJoin
-	handlers[constant.SubEventGroupApply] = h.onConsumeGroupApply
+	h.handlers = make(map[string]ConsumeHandler)
+
+	h.handlers[constant.SubEventImMessage] = h.onConsumeTalk
+	h.handlers[constant.SubEventImMessageKeyboard] = h.onConsumeTalkKeyboard
+	h.handlers[constant.SubEventImMessageRead] = h.onConsumeTalkRead
+	h.handlers[constant.SubEventImMessageRevoke] = h.onConsumeTalkRevoke
+	h.handlers[constant.SubEventContactStatus] = h.onConsumeContactStatus
+	h.handlers[constant.SubEventContactApply] = h.onConsumeContactApply
+	h.handlers[constant.SubEventGroupJoin] = h.onConsumeGroupJoin
+	h.handlers[constant.SubEventGroupApply] = h.onConsumeGroupApply
+	h.handlers[constant.SubEventImClientKick] = h.onConsumeClientKick
+	h.handlers[constant.SubEventImSessionReplaced] = h.onConsumeSessionReplaced
+}
+
+// Call 分发订阅事件，单个事件的 panic 不会影响其它事件的消费
+func (h *IMHandler) Call(ctx context.Context, event string, data []byte) (err error) {
+	h.once.Do(h.init)
+
+	return dispatch(ctx, "chat", h.handlers, event, data)
 }
 
-func (h *IMHandler) Call(ctx context.Context, event string, data []byte) {
-	if handlers == nil {
-		h.init()
+// dispatch 按事件调用处理函数并统计消费结果
+func dispatch(ctx context.Context, channel string, handlers map[string]ConsumeHandler, event string, data []byte) (err error) {
+	call, ok := handlers[event]
+	if !ok {
+		consumeCounter.WithLabelValues(event, consumeResultUnknown).Inc()
+		return fmt.Errorf("consume %s event: [%s]未注册回调事件", channel, event)
 	}
 
-	if call, ok := handlers[event]; ok {
-		call(ctx, data)
-	} else {
-		log.Printf("consume chat event: [%s]未注册回调事件\n", event)
+	defer func() {
+		if r := recover(); r != nil {
+			consumeCounter.WithLabelValues(event, consumeResultPanic).Inc()
+			err = fmt.Errorf("consume %s event: [%s] panic: %s", channel, event, utils.PanicTrace(r))
+		}
+	}()
+
+	if err = call(ctx, data); err != nil {
+		consumeCounter.WithLabelValues(event, consumeResultFailure).Inc()
+		return err
 	}
+
+	consumeCounter.WithLabelValues(event, consumeResultSuccess).Inc()
+
+	return nil
 }
@@ -0,0 +1,37 @@
+package consume
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIMHandler_Call(t *testing.T) {
+	h := &IMHandler{}
+	h.once.Do(h.init)
+
+	h.handlers["test.ok"] = func(ctx context.Context, data []byte) error {
+		return nil
+	}
+	h.handlers["test.error"] = func(ctx context.Context, data []byte) error {
+		return errors.New("handle error")
+	}
+	h.handlers["test.panic"] = func(ctx context.Context, data []byte) error {
+		panic("handle panic")
+	}
+
+	ctx := context.Background()
+
+	assert.NoError(t, h.Call(ctx, "test.ok", nil))
+	assert.Error(t, h.Call(ctx, "test.error", nil))
+	assert.Error(t, h.Call(ctx, "test.panic", nil))
+	assert.Error(t, h.Call(ctx, "test.unknown", nil))
+
+	assert.Equal(t, float64(1), testutil.ToFloat64(consumeCounter.WithLabelValues("test.ok", consumeResultSuccess)))
+	assert.Equal(t, float64(1), testutil.ToFloat64(consumeCounter.WithLabelValues("test.error", consumeResultFailure)))
+	assert.Equal(t, float64(1), testutil.ToFloat64(consumeCounter.WithLabelValues("test.panic", consumeResultPanic)))
+	assert.Equal(t, float64(1), testutil.ToFloat64(consumeCounter.WithLabelValues("test.unknown", consumeResultUnknown)))
+}
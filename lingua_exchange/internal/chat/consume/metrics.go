@@ -0,0 +1,21 @@
+package consume
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const (
+	consumeResultSuccess = "success" // 处理成功
+	consumeResultFailure = "failure" // 处理失败
+	consumeResultPanic   = "panic"   // 处理异常
+	consumeResultUnknown = "unknown" // 未注册事件
+)
+
+// consumeCounter 按事件统计订阅消息的消费结果
+var consumeCounter = promauto.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "im",
+	Subsystem: "consume",
+	Name:      "events_total",
+	Help:      "Total number of consumed subscribe events by event and result.",
+}, []string{"event", "result"})
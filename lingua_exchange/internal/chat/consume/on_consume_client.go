@@ -0,0 +1,78 @@
+package consume
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"lingua_exchange/internal/constant"
+	"lingua_exchange/internal/types"
+	"lingua_exchange/pkg/socket"
+)
+
+const (
+	clientKickedCloseCode    = 4003 // 强制断开连接时的关闭码
+	sessionReplacedCloseCode = 4004 // 被同平台新连接替换时的关闭码
+)
+
+// 强制断开用户在本节点各渠道的连接，推送 im.kicked 后关闭
+func (h *IMHandler) onConsumeClientKick(ctx context.Context, body []byte) error {
+
+	var in types.ConsumeClientKick
+	if err := json.Unmarshal(body, &in); err != nil {
+		return fmt.Errorf("[ChatSubscribe] onConsumeClientKick Unmarshal err: %w", err)
+	}
+
+	for _, ch := range socket.Session.Channels() {
+		for _, cid := range ch.ClientIds(in.UserID) {
+			client, ok := ch.Client(cid)
+			if !ok || !matchKick(client.Meta(), &in) {
+				continue
+			}
+
+			client.Shutdown(&socket.ClientResponse{
+				Event:   constant.PushEventImKicked,
+				Content: &types.ImKicked{Reason: in.Reason},
+			}, clientKickedCloseCode, "kicked: "+in.Reason)
+		}
+	}
+
+	return nil
+}
+
+// 断开被同平台新连接替换的本节点连接，推送 im.session.replaced 后关闭
+func (h *IMHandler) onConsumeSessionReplaced(ctx context.Context, body []byte) error {
+
+	var in types.ConsumeSessionReplaced
+	if err := json.Unmarshal(body, &in); err != nil {
+		return fmt.Errorf("[ChatSubscribe] onConsumeSessionReplaced Unmarshal err: %w", err)
+	}
+
+	ch, ok := socket.Session.Channel(in.Channel)
+	if !ok {
+		return nil
+	}
+
+	for _, cid := range in.Cids {
+		client, ok := ch.Client(cid)
+		if !ok || client.Uid() != in.UserID {
+			continue
+		}
+
+		client.Shutdown(&socket.ClientResponse{
+			Event:   constant.PushEventImSessionReplaced,
+			Content: &types.ImSessionReplaced{Platform: in.Platform, Device: in.Device},
+		}, sessionReplacedCloseCode, "session replaced")
+	}
+
+	return nil
+}
+
+// matchKick 连接是否属于指定的平台及设备
+func matchKick(meta *socket.ClientMeta, in *types.ConsumeClientKick) bool {
+	if in.Platform != "" && meta.Platform != in.Platform {
+		return false
+	}
+
+	return in.Device == "" || meta.Device == in.Device
+}
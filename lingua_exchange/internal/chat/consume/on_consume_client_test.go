@@ -0,0 +1,19 @@
+package consume
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"lingua_exchange/internal/types"
+	"lingua_exchange/pkg/socket"
+)
+
+func TestMatchKick(t *testing.T) {
+	meta := &socket.ClientMeta{Platform: "ios", Device: "d1"}
+
+	assert.True(t, matchKick(meta, &types.ConsumeClientKick{UserID: 1}))
+	assert.True(t, matchKick(meta, &types.ConsumeClientKick{UserID: 1, Platform: "ios"}))
+	assert.True(t, matchKick(meta, &types.ConsumeClientKick{UserID: 1, Device: "d1"}))
+	assert.False(t, matchKick(meta, &types.ConsumeClientKick{UserID: 1, Platform: "android"}))
+	assert.False(t, matchKick(meta, &types.ConsumeClientKick{UserID: 1, Platform: "ios", Device: "d2"}))
+}
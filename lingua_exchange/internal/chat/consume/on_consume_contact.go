@@ -3,22 +3,23 @@ package consume
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 
-	"github.com/zhufuyi/sponge/pkg/logger"
 	"lingua_exchange/internal/types"
 )
 
 // 用户上线或下线消息
-func (h *IMHandler) onConsumeContactStatus(ctx context.Context, body []byte) {
+func (h *IMHandler) onConsumeContactStatus(ctx context.Context, body []byte) error {
 
 	var in types.ConsumeContactStatus
 	if err := json.Unmarshal(body, &in); err != nil {
-		logger.Errorf("[ChatSubscribe] onConsumeContactStatus Unmarshal err: %s", err.Error())
-		return
+		return fmt.Errorf("[ChatSubscribe] onConsumeContactStatus Unmarshal err: %w", err)
 	}
+
+	return nil
 }
 
 // 好友申请消息
-func (h *IMHandler) onConsumeContactApply(ctx context.Context, body []byte) {
-
+func (h *IMHandler) onConsumeContactApply(ctx context.Context, body []byte) error {
+	return nil
 }
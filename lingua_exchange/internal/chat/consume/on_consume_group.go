@@ -3,9 +3,9 @@ package consume
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"strconv"
 
-	"github.com/zhufuyi/sponge/pkg/logger"
 	"lingua_exchange/internal/constant"
 	"lingua_exchange/internal/model"
 	"lingua_exchange/internal/types"
@@ -13,19 +13,15 @@ import (
 )
 
 // 加入群房间
-func (h *IMHandler) onConsumeGroupJoin(ctx context.Context, body []byte) {
+func (h *IMHandler) onConsumeGroupJoin(ctx context.Context, body []byte) error {
 
 	var in types.ConsumeGroupJoin
 	if err := json.Unmarshal(body, &in); err != nil {
-		logger.Errorf("[ChatSubscribe] onConsumeGroupJoin Unmarshal err: %s", err.Error())
-		return
+		return fmt.Errorf("[ChatSubscribe] onConsumeGroupJoin Unmarshal err: %w", err)
 	}
 
-	sid := h.config.App.Sid
 	for _, uid := range in.Uids {
-		ids := h.messageCache.GetUidFromClientIds(ctx, sid, socket.Session.Chat.Name(), strconv.Itoa(uid))
-
-		for _, cid := range ids {
+		for _, cid := range socket.Session.Chat.ClientIds(uid) {
 			opt := &types.RoomOption{
 				Channel:  socket.Session.Chat.Name(),
 				RoomType: constant.RoomImGroup,
@@ -41,41 +37,42 @@ func (h *IMHandler) onConsumeGroupJoin(ctx context.Context, body []byte) {
 			}
 		}
 	}
+
+	return nil
 }
 
 // 入群申请通知
-func (h *IMHandler) onConsumeGroupApply(ctx context.Context, body []byte) {
+func (h *IMHandler) onConsumeGroupApply(ctx context.Context, body []byte) error {
 
 	var in types.ConsumeGroupApply
 	if err := json.Unmarshal(body, &in); err != nil {
-		logger.Errorf("[ChatSubscribe] onConsumeGroupApply Unmarshal err: %s", err.Error())
-		return
+		return fmt.Errorf("[ChatSubscribe] onConsumeGroupApply Unmarshal err: %w", err)
 	}
 
 	var groupMember model.GroupMember
 	if err := h.db.First(&groupMember, "group_id = ? and leader = ?", in.GroupId, 2).Error; err != nil {
-		return
+		return err
 	}
 
 	var groupDetail model.Group
 	if err := h.db.First(&groupDetail, in.GroupId).Error; err != nil {
-		return
+		return err
 	}
 
 	var user model.Users
 	if err := h.db.First(&user, in.UserId).Error; err != nil {
-		return
+		return err
 	}
 
 	data := make(map[string]any)
 	data["group_name"] = groupDetail.Name
 	data["username"] = user.Username
 
-	clientIds := h.messageCache.GetUidFromClientIds(ctx, h.config.App.Sid, socket.Session.Chat.Name(), strconv.Itoa(groupMember.UserID))
-
 	c := socket.NewSenderContent()
-	c.SetReceive(clientIds...)
+	c.SetReceiveUsers(groupMember.UserID)
 	c.SetMessage(constant.PushEventGroupApply, data)
 
 	socket.Session.Chat.Write(c)
+
+	return nil
 }
@@ -3,34 +3,29 @@ package consume
 import (
 	"context"
 	"encoding/json"
-	"strconv"
+	"fmt"
 
-	"github.com/zhufuyi/sponge/pkg/logger"
 	"lingua_exchange/internal/constant"
 	"lingua_exchange/internal/types"
 	"lingua_exchange/pkg/socket"
 )
 
 // 键盘输入事件消息
-func (h *IMHandler) onConsumeTalkKeyboard(ctx context.Context, body []byte) {
+func (h *IMHandler) onConsumeTalkKeyboard(ctx context.Context, body []byte) error {
 
 	var in types.ConsumeTalkKeyboard
 	if err := json.Unmarshal(body, &in); err != nil {
-		logger.Errorf("[ChatSubscribe] onConsumeTalkKeyboard Unmarshal err: %s", err.Error())
-		return
-	}
-
-	ids := h.messageCache.GetUidFromClientIds(ctx, h.config.App.Sid, socket.Session.Chat.Name(), strconv.Itoa(in.ReceiverID))
-	if len(ids) == 0 {
-		return
+		return fmt.Errorf("[ChatSubscribe] onConsumeTalkKeyboard Unmarshal err: %w", err)
 	}
 
 	c := socket.NewSenderContent()
-	c.SetReceive(ids...)
+	c.SetReceiveUsers(in.ReceiverID)
 	c.SetMessage(constant.PushEventImMessageKeyboard, map[string]any{
 		"sender_id":   in.SenderID,
 		"receiver_id": in.ReceiverID,
 	})
 
 	socket.Session.Chat.Write(c)
+
+	return nil
 }
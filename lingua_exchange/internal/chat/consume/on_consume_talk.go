@@ -3,29 +3,30 @@ package consume
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"strconv"
+	"time"
 
-	"github.com/zhufuyi/sponge/pkg/logger"
 	"lingua_exchange/internal/constant"
 	"lingua_exchange/internal/types"
 	"lingua_exchange/pkg/socket"
 )
 
+// 同一条消息在本节点推送后的去重时长，需覆盖发件箱的补偿投递周期
+const talkConsumedTTL = 10 * time.Minute
+
 // 聊天消息事件
-func (h *IMHandler) onConsumeTalk(ctx context.Context, body []byte) {
+func (h *IMHandler) onConsumeTalk(ctx context.Context, body []byte) error {
 
 	var in types.ConsumeTalk
 	if err := json.Unmarshal(body, &in); err != nil {
-		logger.Errorf("[ChatSubscribe] onConsumeTalk Unmarshal err: %s", err.Error())
-		return
+		return fmt.Errorf("[ChatSubscribe] onConsumeTalk Unmarshal err: %w", err)
 	}
 
 	var clientIds []int64
 	if in.TalkType == constant.ChatPrivateMode {
 		for _, val := range [2]int64{in.SenderID, in.ReceiverID} {
-			ids := h.messageCache.GetUidFromClientIds(ctx, h.config.App.Sid, socket.Session.Chat.Name(), strconv.FormatInt(val, 10))
-
-			clientIds = append(clientIds, ids...)
+			clientIds = append(clientIds, socket.Session.Chat.ClientIds(int(val))...)
 		}
 	} else if in.TalkType == constant.ChatGroupMode {
 		ids := h.chatRoom.All(ctx, &types.RoomOption{
@@ -39,12 +40,18 @@ func (h *IMHandler) onConsumeTalk(ctx context.Context, body []byte) {
 	}
 
 	if len(clientIds) == 0 {
-		return
+		return nil
+	}
+
+	// 发件箱补偿或部分节点失败重投时，已推送过的节点不再重复推送
+	if h.consumed(ctx, in.MsgId) {
+		return nil
 	}
 
 	data, err := h.talkRecordsDao.FindTalkRecord(ctx, in.MsgId)
 	if err != nil {
-		return
+		h.unconsumed(ctx, in.MsgId)
+		return err
 	}
 
 	c := socket.NewSenderContent()
@@ -58,4 +65,31 @@ func (h *IMHandler) onConsumeTalk(ctx context.Context, body []byte) {
 	})
 
 	socket.Session.Chat.Write(c)
+
+	return nil
+}
+
+// consumed 标记本节点已推送该消息，已标记过时返回 true
+func (h *IMHandler) consumed(ctx context.Context, msgId string) bool {
+	if h.redis == nil {
+		return false
+	}
+
+	ok, err := h.redis.SetNX(ctx, h.consumedKey(msgId), 1, talkConsumedTTL).Result()
+	if err != nil {
+		return false // 去重不可用时宁可重复推送也不丢消息
+	}
+
+	return !ok
+}
+
+// unconsumed 推送失败时取消标记，等待重新投递
+func (h *IMHandler) unconsumed(ctx context.Context, msgId string) {
+	if h.redis != nil {
+		h.redis.Del(ctx, h.consumedKey(msgId))
+	}
+}
+
+func (h *IMHandler) consumedKey(msgId string) string {
+	return fmt.Sprintf("im:consume:talk:%s:%s", h.config.App.Sid, msgId)
 }
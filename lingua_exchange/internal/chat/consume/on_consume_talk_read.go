@@ -3,30 +3,23 @@ package consume
 import (
 	"context"
 	"encoding/json"
-	"strconv"
+	"fmt"
 
-	"github.com/zhufuyi/sponge/pkg/logger"
 	"lingua_exchange/internal/constant"
 	"lingua_exchange/internal/types"
 	"lingua_exchange/pkg/socket"
 )
 
 // 消息已读事件
-func (h *IMHandler) onConsumeTalkRead(ctx context.Context, body []byte) {
+func (h *IMHandler) onConsumeTalkRead(ctx context.Context, body []byte) error {
 	var in types.ConsumeTalkRead
 	if err := json.Unmarshal(body, &in); err != nil {
-		logger.Errorf("[ChatSubscribe] onConsumeContactApply Unmarshal err: %s", err.Error())
-		return
-	}
-
-	clientIds := h.messageCache.GetUidFromClientIds(ctx, h.config.App.Sid, socket.Session.Chat.Name(), strconv.Itoa(in.ReceiverId))
-	if len(clientIds) == 0 {
-		return
+		return fmt.Errorf("[ChatSubscribe] onConsumeTalkRead Unmarshal err: %w", err)
 	}
 
 	c := socket.NewSenderContent()
 	c.SetAck(true)
-	c.SetReceive(clientIds...)
+	c.SetReceiveUsers(in.ReceiverId)
 	c.SetMessage(constant.PushEventImMessageRead, map[string]any{
 		"sender_id":   in.SenderId,
 		"receiver_id": in.ReceiverId,
@@ -34,4 +27,6 @@ func (h *IMHandler) onConsumeTalkRead(ctx context.Context, body []byte) {
 	})
 
 	socket.Session.Chat.Write(c)
+
+	return nil
 }
@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"strconv"
 
-	"github.com/zhufuyi/sponge/pkg/logger"
 	"lingua_exchange/internal/constant"
 	"lingua_exchange/internal/model"
 	"lingua_exchange/internal/types"
@@ -14,23 +13,21 @@ import (
 )
 
 // 撤销聊天消息
-func (h *IMHandler) onConsumeTalkRevoke(ctx context.Context, body []byte) {
+func (h *IMHandler) onConsumeTalkRevoke(ctx context.Context, body []byte) error {
 	var in types.ConsumeTalkRevoke
 	if err := json.Unmarshal(body, &in); err != nil {
-		logger.Errorf("[ChatSubscribe] onConsumeTalkRevoke Unmarshal err: %s", err.Error())
-		return
+		return fmt.Errorf("[ChatSubscribe] onConsumeTalkRevoke Unmarshal err: %w", err)
 	}
 
 	var record model.TalkRecords
 	if err := h.db.First(&record, "msg_id = ?", in.MsgId).Error; err != nil {
-		return
+		return err
 	}
 
 	var clientIds []int64
 	if record.TalkType == constant.ChatPrivateMode {
 		for _, uid := range [2]int{record.UserID, record.ReceiverID} {
-			ids := h.messageCache.GetUidFromClientIds(ctx, h.config.App.Sid, socket.Session.Chat.Name(), strconv.Itoa(uid))
-			clientIds = append(clientIds, ids...)
+			clientIds = append(clientIds, socket.Session.Chat.ClientIds(uid)...)
 		}
 	} else if record.TalkType == constant.ChatGroupMode {
 		clientIds = h.chatRoom.All(ctx, &types.RoomOption{
@@ -42,12 +39,12 @@ func (h *IMHandler) onConsumeTalkRevoke(ctx context.Context, body []byte) {
 	}
 
 	if len(clientIds) == 0 {
-		return
+		return nil
 	}
 
 	var user model.Users
 	if err := h.db.WithContext(ctx).Select("id,nickname").First(&user, record.UserID).Error; err != nil {
-		return
+		return err
 	}
 
 	c := socket.NewSenderContent()
@@ -62,4 +59,6 @@ func (h *IMHandler) onConsumeTalkRevoke(ctx context.Context, body []byte) {
 	})
 
 	socket.Session.Chat.Write(c)
+
+	return nil
 }
@@ -0,0 +1,37 @@
+package consume
+
+import (
+	"context"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"lingua_exchange/internal/config"
+)
+
+func TestIMHandler_consumed(t *testing.T) {
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	defer rdb.Close()
+
+	ctx := context.Background()
+	node1 := &IMHandler{redis: rdb, config: &config.Config{App: config.App{Sid: "node-1"}}}
+	node2 := &IMHandler{redis: rdb, config: &config.Config{App: config.App{Sid: "node-2"}}}
+
+	// 同一节点重复投递的消息只推送一次
+	assert.False(t, node1.consumed(ctx, "m1"))
+	assert.True(t, node1.consumed(ctx, "m1"))
+	assert.False(t, node1.consumed(ctx, "m2"))
+
+	// 其它节点各自推送
+	assert.False(t, node2.consumed(ctx, "m1"))
+
+	// 推送失败取消标记后可以重新推送
+	node1.unconsumed(ctx, "m1")
+	assert.False(t, node1.consumed(ctx, "m1"))
+
+	// 去重过期后不再拦截
+	mr.FastForward(talkConsumedTTL)
+	assert.False(t, node2.consumed(ctx, "m1"))
+}
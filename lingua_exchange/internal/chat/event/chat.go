@@ -1,31 +1,117 @@
 package event
 
 import (
+	"context"
 	"log"
+	"strconv"
+	"sync"
+	"time"
 
 	"github.com/redis/go-redis/v9"
+	"github.com/zhufuyi/sponge/pkg/logger"
+	"gorm.io/gorm"
+	"lingua_exchange/internal/bus"
 	"lingua_exchange/internal/cache"
 	"lingua_exchange/internal/config"
+	"lingua_exchange/internal/constant"
 	"lingua_exchange/internal/dao"
+	"lingua_exchange/internal/imService"
+	"lingua_exchange/internal/types"
 	"lingua_exchange/pkg/socket"
 )
 
 type ChatEvent struct {
-	Redis           *redis.Client
-	Config          *config.Config
-	RoomStorage     cache.ChatRoomCache
-	GroupMemberRepo dao.GroupMemberDao
+	Redis             *redis.Client
+	DB                *gorm.DB
+	Config            *config.Config
+	RoomStorage       cache.ChatRoomCache
+	GroupMemberRepo   dao.GroupMemberDao
+	TalkRecordsDao    dao.TalkRecordsDao
+	TalkSessionDao    dao.TalkSessionDao
+	UnreadCache       cache.UnreadCache
+	MessageCache      *cache.MessageCache
+	MessageService    imService.IMessageService
+	PermissionService imService.IPermissionService
+	Publisher         bus.Publisher
+
+	once       sync.Once
+	dispatcher *Dispatcher
+	publishers map[string]publishHandler
+	tokens     *tokenGuard
 }
 
 func (c *ChatEvent) OnOpen(client socket.IClient) {
 	log.Println("OnOpen client:", client)
 
+	// 加入所在群聊房间，用于群消息推送
+	if opts := c.groupRooms(client); len(opts) > 0 {
+		if err := c.RoomStorage.BatchAdd(context.Background(), opts); err != nil {
+			logger.Warn("join group rooms error", logger.Int("uid", client.Uid()), logger.Err(err))
+		}
+	}
+}
+
+// WatchToken 跟踪连接令牌有效期，过期后推送 token.expired 并在宽限期后断开
+func (c *ChatEvent) WatchToken(client socket.IClient, expireAt time.Time) {
+	c.once.Do(c.init)
+
+	c.tokens.watch(client, expireAt)
 }
 
 func (c *ChatEvent) OnMessage(client socket.IClient, message []byte) {
-	log.Println("OnMessage client:", client)
+	c.once.Do(c.init)
+
+	c.dispatcher.Dispatch(client, message)
 }
 
 func (c *ChatEvent) OnClose(client socket.IClient, code int, text string) {
 	log.Println("OnClose client:", client)
+
+	c.once.Do(c.init)
+	c.tokens.remove(client.Cid())
+
+	if opts := c.groupRooms(client); len(opts) > 0 {
+		if err := c.RoomStorage.BatchDel(context.Background(), opts); err != nil {
+			logger.Warn("leave group rooms error", logger.Int("uid", client.Uid()), logger.Err(err))
+		}
+	}
+}
+
+// groupRooms 客户端所在的群聊房间
+func (c *ChatEvent) groupRooms(client socket.IClient) []*types.RoomOption {
+	if c.RoomStorage == nil || c.GroupMemberRepo == nil {
+		return nil
+	}
+
+	ids := c.GroupMemberRepo.GetUserGroupIds(context.Background(), client.Uid())
+
+	opts := make([]*types.RoomOption, 0, len(ids))
+	for _, id := range ids {
+		opts = append(opts, &types.RoomOption{
+			Channel:  socket.Session.Chat.Name(),
+			RoomType: constant.RoomImGroup,
+			Number:   strconv.Itoa(id),
+			Sid:      c.Config.App.Sid,
+			Cid:      client.Cid(),
+		})
+	}
+
+	return opts
+}
+
+// init 注册客户端上行事件
+func (c *ChatEvent) init() {
+	c.dispatcher = NewDispatcher()
+
+	c.dispatcher.Register(constant.EventImMessagePublish, c.onPublish)
+	c.dispatcher.Register(constant.EventImMessageKeyboard, c.onKeyboard)
+	c.dispatcher.Register(constant.EventImMessageRead, c.onRead)
+	c.dispatcher.Register(constant.EventImMessageRevoke, c.onRevoke)
+	c.dispatcher.Register(constant.EventTokenRefresh, c.onTokenRefresh)
+	c.dispatcher.Register(constant.EventSyncResume, c.onSyncResume)
+	c.dispatcher.Register(constant.EventTalkSessionList, c.onSessionList)
+	c.dispatcher.Register(constant.EventTalkRecords, c.onTalkRecords)
+
+	c.publishers = c.publishHandlers()
+	c.tokens = newTokenGuard(tokenExpiredGrace)
 }
@@ -0,0 +1,94 @@
+package event
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/sync/errgroup"
+	"lingua_exchange/internal/cache"
+	"lingua_exchange/internal/config"
+	"lingua_exchange/internal/constant"
+	"lingua_exchange/internal/dao"
+	"lingua_exchange/internal/imService"
+	"lingua_exchange/internal/types"
+	"lingua_exchange/pkg/socket"
+)
+
+// fakeRoomCache 记录房间的加入和退出
+type fakeRoomCache struct {
+	cache.ChatRoomCache
+	added   []*types.RoomOption
+	removed []*types.RoomOption
+}
+
+func (f *fakeRoomCache) BatchAdd(_ context.Context, opts []*types.RoomOption) error {
+	f.added = append(f.added, opts...)
+	return nil
+}
+
+func (f *fakeRoomCache) BatchDel(_ context.Context, opts []*types.RoomOption) error {
+	f.removed = append(f.removed, opts...)
+	return nil
+}
+
+// fakeGroupMemberRepo 返回固定的群聊列表
+type fakeGroupMemberRepo struct {
+	dao.GroupMemberDao
+	groups map[int][]int
+}
+
+func (f *fakeGroupMemberRepo) GetUserGroupIds(_ context.Context, uid int) []int {
+	return f.groups[uid]
+}
+
+// roomClient 带客户端ID的测试客户端
+type roomClient struct {
+	fakeClient
+	cid int64
+}
+
+func (r *roomClient) Cid() int64 {
+	return r.cid
+}
+
+func TestChatEvent_GroupRooms(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	eg, groupCtx := errgroup.WithContext(ctx)
+	socket.Initialize(groupCtx, eg, func(name string) {}, nil)
+
+	rooms := &fakeRoomCache{}
+	c := &ChatEvent{
+		Config:          &config.Config{App: config.App{Sid: "sid-test"}},
+		RoomStorage:     rooms,
+		GroupMemberRepo: &fakeGroupMemberRepo{groups: map[int][]int{1: {10, 20}}},
+		MessageService:  &imService.MessageService{},
+	}
+
+	client := &roomClient{cid: 100}
+
+	c.OnOpen(client)
+	assert.Len(t, rooms.added, 2)
+	for i, number := range []string{"10", "20"} {
+		assert.Equal(t, &types.RoomOption{
+			Channel:  socket.Session.Chat.Name(),
+			RoomType: constant.RoomImGroup,
+			Number:   number,
+			Sid:      "sid-test",
+			Cid:      100,
+		}, rooms.added[i])
+	}
+
+	c.OnClose(client, 1000, "")
+	assert.Equal(t, rooms.added, rooms.removed)
+
+	// 未加入任何群聊时不操作房间
+	rooms.added, rooms.removed = nil, nil
+	c.GroupMemberRepo = &fakeGroupMemberRepo{}
+	c.OnOpen(client)
+	c.OnClose(client, 1000, "")
+	assert.Empty(t, rooms.added)
+	assert.Empty(t, rooms.removed)
+}
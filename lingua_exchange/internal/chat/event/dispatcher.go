@@ -0,0 +1,149 @@
+package event
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/zhufuyi/sponge/pkg/errcode"
+	"github.com/zhufuyi/sponge/pkg/logger"
+	"lingua_exchange/internal/constant"
+	"lingua_exchange/internal/ecode"
+	"lingua_exchange/internal/types"
+	"lingua_exchange/pkg/socket"
+)
+
+// 单个客户端事件的最长处理时间
+const eventHandleTimeout = 10 * time.Second
+
+// EventHandler 客户端上行事件处理函数，返回的数据会放入成功回执中
+type EventHandler func(ctx context.Context, client socket.IClient, content []byte) (any, error)
+
+// eventMessage 客户端上行消息结构，携带 req_id 时以 rpc.reply 回执
+type eventMessage struct {
+	Event   string          `json:"event"`
+	ReqId   string          `json:"req_id"`
+	Content json.RawMessage `json:"content"`
+}
+
+// Dispatcher 客户端事件分发器
+type Dispatcher struct {
+	handlers map[string]EventHandler
+	timeout  time.Duration
+}
+
+func NewDispatcher() *Dispatcher {
+	return &Dispatcher{handlers: make(map[string]EventHandler), timeout: eventHandleTimeout}
+}
+
+// Register 注册事件处理函数，重复注册会覆盖之前的处理函数
+func (d *Dispatcher) Register(event string, handler EventHandler) {
+	d.handlers[event] = handler
+}
+
+// Dispatch 解析客户端消息并路由到对应的处理函数
+func (d *Dispatcher) Dispatch(client socket.IClient, data []byte) {
+	var in eventMessage
+	if err := json.Unmarshal(data, &in); err != nil || in.Event == "" {
+		d.reply(client, &in, nil, ecode.ErrEventInvalid.Err())
+		return
+	}
+
+	call, ok := d.handlers[in.Event]
+	if !ok {
+		d.reply(client, &in, nil, ecode.ErrEventNotSupported.Err())
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), d.timeout)
+	defer cancel()
+
+	result, err := d.call(ctx, call, client, in.Content)
+	if err != nil {
+		logger.Warn("socket event handle error", logger.String("event", in.Event), logger.Int("uid", client.Uid()), logger.Err(err))
+	}
+
+	d.reply(client, &in, result, err)
+}
+
+// call 执行事件处理函数，超时后直接返回超时错误，处理函数需自行响应 ctx 取消
+func (d *Dispatcher) call(ctx context.Context, handler EventHandler, client socket.IClient, content []byte) (any, error) {
+	type output struct {
+		data any
+		err  error
+	}
+
+	done := make(chan output, 1)
+	go func() {
+		defer func() {
+			if e := recover(); e != nil {
+				done <- output{err: fmt.Errorf("socket event panic: %v", e)}
+			}
+		}()
+
+		data, err := handler(ctx, client, content)
+		done <- output{data: data, err: err}
+	}()
+
+	select {
+	case out := <-done:
+		return out.data, out.err
+	case <-ctx.Done():
+		return nil, ecode.ErrEventTimeout.Err()
+	}
+}
+
+// reply 回写事件处理结果
+func (d *Dispatcher) reply(client socket.IClient, in *eventMessage, data any, err error) {
+	code, msg := 0, "ok"
+	if err != nil {
+		e := errcode.ParseError(err)
+		if e.Code() == -1 {
+			e = ecode.InternalServerError
+		}
+
+		code, msg, data = e.Code(), e.Msg(), nil
+	}
+
+	if in.ReqId != "" {
+		_ = client.Write(&socket.ClientResponse{
+			Event: constant.PushEventRpcReply,
+			ReqId: in.ReqId,
+			Code:  &code,
+			Msg:   msg,
+			Data:  data,
+		})
+		return
+	}
+
+	name := constant.PushEventSuccess
+	if err != nil {
+		name = constant.PushEventError
+	}
+
+	_ = client.Write(&socket.ClientResponse{
+		Event:   name,
+		Content: &types.EventReply{Event: in.Event, Code: code, Msg: msg, Data: data},
+	})
+}
+
+// bind 解析并校验事件内容
+func bind(content []byte, obj any) error {
+	if len(content) == 0 {
+		return ecode.ErrEventParams.Err()
+	}
+
+	if err := json.Unmarshal(content, obj); err != nil {
+		return ecode.ErrEventParams.Err(err.Error())
+	}
+
+	if binding.Validator != nil {
+		if err := binding.Validator.ValidateStruct(obj); err != nil {
+			return ecode.ErrEventParams.Err(err.Error())
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,78 @@
+package event
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"lingua_exchange/internal/constant"
+	"lingua_exchange/internal/ecode"
+	"lingua_exchange/internal/types"
+	"lingua_exchange/pkg/socket"
+)
+
+// fakeClient 记录推送给客户端的数据
+type fakeClient struct {
+	socket.IClient
+	out []*socket.ClientResponse
+}
+
+func (f *fakeClient) Uid() int {
+	return 1
+}
+
+func (f *fakeClient) Write(data *socket.ClientResponse) error {
+	f.out = append(f.out, data)
+	return nil
+}
+
+func TestDispatcher_Rpc(t *testing.T) {
+	d := NewDispatcher()
+	d.timeout = 50 * time.Millisecond
+
+	d.Register("echo", func(_ context.Context, _ socket.IClient, content []byte) (any, error) {
+		return string(content), nil
+	})
+	d.Register("fail", func(context.Context, socket.IClient, []byte) (any, error) {
+		return nil, ecode.ErrEventParams.Err()
+	})
+	d.Register("slow", func(ctx context.Context, _ socket.IClient, _ []byte) (any, error) {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+	d.Register("panic", func(context.Context, socket.IClient, []byte) (any, error) {
+		panic("boom")
+	})
+
+	client := &fakeClient{}
+	reply := func(message string) *socket.ClientResponse {
+		d.Dispatch(client, []byte(message))
+		return client.out[len(client.out)-1]
+	}
+
+	out := reply(`{"event":"echo","req_id":"1","content":{"a":1}}`)
+	assert.Equal(t, constant.PushEventRpcReply, out.Event)
+	assert.Equal(t, "1", out.ReqId)
+	assert.Equal(t, 0, *out.Code)
+	assert.Equal(t, `{"a":1}`, out.Data)
+
+	out = reply(`{"event":"fail","req_id":"2"}`)
+	assert.Equal(t, ecode.ErrEventParams.Code(), *out.Code)
+	assert.Nil(t, out.Data)
+
+	out = reply(`{"event":"slow","req_id":"3"}`)
+	assert.Equal(t, ecode.ErrEventTimeout.Code(), *out.Code)
+
+	out = reply(`{"event":"panic","req_id":"4"}`)
+	assert.Equal(t, ecode.InternalServerError.Code(), *out.Code)
+
+	out = reply(`{"event":"unknown","req_id":"5"}`)
+	assert.Equal(t, ecode.ErrEventNotSupported.Code(), *out.Code)
+
+	// 未携带 req_id 时保持原有回执
+	out = reply(`{"event":"fail"}`)
+	assert.Equal(t, constant.PushEventError, out.Event)
+	assert.Equal(t, ecode.ErrEventParams.Code(), out.Content.(*types.EventReply).Code)
+	assert.Empty(t, out.ReqId)
+}
@@ -0,0 +1,30 @@
+package event
+
+import (
+	"context"
+
+	"lingua_exchange/internal/bus"
+	"lingua_exchange/internal/constant"
+	"lingua_exchange/internal/ecode"
+	"lingua_exchange/internal/types"
+	"lingua_exchange/pkg/socket"
+)
+
+// 键盘输入事件
+func (c *ChatEvent) onKeyboard(ctx context.Context, client socket.IClient, content []byte) (any, error) {
+	params := &types.EventTalkKeyboard{}
+	if err := bind(content, params); err != nil {
+		return nil, err
+	}
+
+	body := bus.NewMessage(constant.SubEventImMessageKeyboard, map[string]any{
+		"sender_id":   client.Uid(),
+		"receiver_id": params.ReceiverId,
+	})
+
+	if err := c.Publisher.Publish(ctx, constant.ImTopicChat, body); err != nil {
+		return nil, ecode.ErrKeyboardMessageError.Err(err.Error())
+	}
+
+	return nil, nil
+}
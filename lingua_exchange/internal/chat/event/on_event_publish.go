@@ -0,0 +1,72 @@
+package event
+
+import (
+	"context"
+
+	"lingua_exchange/internal/constant"
+	"lingua_exchange/internal/ecode"
+	"lingua_exchange/internal/types"
+	"lingua_exchange/pkg/socket"
+)
+
+type publishHandler func(ctx *context.Context, uid int, content []byte) error
+
+// 发送聊天消息事件
+func (c *ChatEvent) onPublish(ctx context.Context, client socket.IClient, content []byte) (any, error) {
+	params := &types.PublishBaseMessageRequest{}
+	if err := bind(content, params); err != nil {
+		return nil, err
+	}
+
+	// 私聊暂不做权限校验，与 IPermissionService 保持一致
+	if params.Receiver.TalkType == constant.ChatGroupMode {
+		if err := c.PermissionService.IsAuth(ctx, &types.AuthOption{
+			TalkType:          params.Receiver.TalkType,
+			UserId:            client.Uid(),
+			ReceiverId:        uint64(params.Receiver.ReceiverId),
+			IsVerifyGroupMute: true,
+		}); err != nil {
+			return nil, ecode.ErrPublishPermissionError.Err(err.Error())
+		}
+	}
+
+	call, ok := c.publishers[params.Type]
+	if !ok {
+		return nil, ecode.ErrMessageTypeNotSupported.Err()
+	}
+
+	return nil, call(&ctx, client.Uid(), content)
+}
+
+func (c *ChatEvent) publishHandlers() map[string]publishHandler {
+	return map[string]publishHandler{
+		constant.Text:     newPublishHandler(c.MessageService.SendText),
+		constant.Code:     newPublishHandler(c.MessageService.SendCode),
+		constant.Location: newPublishHandler(c.MessageService.SendLocation),
+		constant.Emoticon: newPublishHandler(c.MessageService.SendEmoticon),
+		constant.Vote:     newPublishHandler(c.MessageService.SendVote),
+		constant.Image:    newPublishHandler(c.MessageService.SendImage),
+		constant.Voice:    newPublishHandler(c.MessageService.SendVoice),
+		constant.Video:    newPublishHandler(c.MessageService.SendVideo),
+		constant.File:     newPublishHandler(c.MessageService.SendFile),
+		constant.Card:     newPublishHandler(c.MessageService.SendBusinessCard),
+		constant.Forward:  newPublishHandler(c.MessageService.SendForward),
+		constant.Mixed:    newPublishHandler(c.MessageService.SendMixedMessage),
+	}
+}
+
+// newPublishHandler 将消息内容解析为具体的请求类型后交给 IMessageService 发送
+func newPublishHandler[T any](send func(ctx *context.Context, uid int, req *T) error) publishHandler {
+	return func(ctx *context.Context, uid int, content []byte) error {
+		req := new(T)
+		if err := bind(content, req); err != nil {
+			return err
+		}
+
+		if err := send(ctx, uid, req); err != nil {
+			return ecode.ErrPublishMessageError.Err(err.Error())
+		}
+
+		return nil
+	}
+}
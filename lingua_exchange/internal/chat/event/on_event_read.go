@@ -0,0 +1,75 @@
+package event
+
+import (
+	"context"
+
+	"gorm.io/gorm/clause"
+	"lingua_exchange/internal/bus"
+	"lingua_exchange/internal/constant"
+	"lingua_exchange/internal/ecode"
+	"lingua_exchange/internal/model"
+	"lingua_exchange/internal/types"
+	"lingua_exchange/pkg/socket"
+)
+
+// 消息已读事件
+func (c *ChatEvent) onRead(ctx context.Context, client socket.IClient, content []byte) (any, error) {
+	params := &types.EventTalkRead{}
+	if err := bind(content, params); err != nil {
+		return nil, err
+	}
+
+	msgIds, err := c.readableMsgIds(ctx, client.Uid(), params.ReceiverId, params.MsgIds)
+	if err != nil {
+		return nil, ecode.ErrReadMessageError.Err(err.Error())
+	}
+
+	if len(msgIds) == 0 {
+		return nil, nil
+	}
+
+	items := make([]*model.TalkRecordsRead, 0, len(msgIds))
+	for _, msgId := range msgIds {
+		items = append(items, &model.TalkRecordsRead{
+			MsgID:      msgId,
+			UserID:     uint(client.Uid()),
+			ReceiverID: uint(params.ReceiverId),
+		})
+	}
+
+	// 重复上报的已读记录忽略唯一键冲突，避免整批写入失败
+	if err := c.DB.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&items).Error; err != nil {
+		return nil, ecode.ErrReadMessageError.Err(err.Error())
+	}
+
+	c.UnreadCache.Reset(ctx, constant.ChatPrivateMode, params.ReceiverId, client.Uid())
+
+	body := bus.NewMessage(constant.SubEventImMessageRead, map[string]any{
+		"sender_id":   client.Uid(),
+		"receiver_id": params.ReceiverId,
+		"msg_ids":     msgIds,
+	})
+
+	if err := c.Publisher.Publish(ctx, constant.ImTopicChat, body); err != nil {
+		return nil, ecode.ErrReadMessageError.Err(err.Error())
+	}
+
+	return nil, nil
+}
+
+// 过滤出对方发给当前用户的私信消息，忽略不存在或不属于该会话的消息ID
+func (c *ChatEvent) readableMsgIds(ctx context.Context, uid int, receiverId int, msgIds []string) ([]string, error) {
+	if len(msgIds) == 0 {
+		return nil, nil
+	}
+
+	items := make([]string, 0, len(msgIds))
+	err := c.DB.WithContext(ctx).Model(&model.TalkRecords{}).
+		Where("talk_type = ? and user_id = ? and receiver_id = ? and msg_id in ?", constant.ChatPrivateMode, receiverId, uid, msgIds).
+		Distinct().Pluck("msg_id", &items).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
@@ -0,0 +1,85 @@
+package event
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/zhufuyi/sponge/pkg/gotest"
+	"lingua_exchange/internal/cache"
+	"lingua_exchange/internal/types"
+)
+
+// fakeUnreadCache 记录未读数清零
+type fakeUnreadCache struct {
+	cache.UnreadCache
+	resets int
+}
+
+func (f *fakeUnreadCache) Reset(context.Context, int, int, int) {
+	f.resets++
+}
+
+// fakePublisher 记录发布的消息
+type fakePublisher struct {
+	topics []string
+}
+
+func (f *fakePublisher) Publish(_ context.Context, topic string, _ *types.SubscribeContent) error {
+	f.topics = append(f.topics, topic)
+	return nil
+}
+
+func TestChatEvent_onRead(t *testing.T) {
+	d := gotest.NewDao(nil, nil)
+	defer d.Close()
+
+	c := &ChatEvent{DB: d.DB}
+
+	// 只保留对方发给当前用户的消息
+	d.SQLMock.ExpectQuery("SELECT DISTINCT `msg_id` FROM `talk_records` WHERE .*").
+		WithArgs(1, 2, 1, "a", "b").
+		WillReturnRows(sqlmock.NewRows([]string{"msg_id"}).AddRow("a"))
+
+	msgIds, err := c.readableMsgIds(context.Background(), 1, 2, []string{"a", "b"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"a"}, msgIds)
+
+	// 没有可读消息时不写入已读记录也不推送
+	d.SQLMock.ExpectQuery("SELECT DISTINCT `msg_id` FROM `talk_records` WHERE .*").
+		WillReturnRows(sqlmock.NewRows([]string{"msg_id"}))
+
+	out, err := c.onRead(context.Background(), &fakeClient{}, []byte(`{"receiver_id":2,"msg_ids":["c"]}`))
+	assert.NoError(t, err)
+	assert.Nil(t, out)
+	assert.NoError(t, d.SQLMock.ExpectationsWereMet())
+}
+
+func TestChatEvent_onReadTwice(t *testing.T) {
+	d := gotest.NewDao(nil, nil)
+	defer d.Close()
+
+	unread := &fakeUnreadCache{}
+	publisher := &fakePublisher{}
+	c := &ChatEvent{DB: d.DB, UnreadCache: unread, Publisher: publisher}
+
+	// 重复上报同一条消息已读，第二次命中唯一键时忽略
+	for _, affected := range []int64{1, 0} {
+		d.SQLMock.ExpectQuery("SELECT DISTINCT `msg_id` FROM `talk_records` WHERE .*").
+			WillReturnRows(sqlmock.NewRows([]string{"msg_id"}).AddRow("a"))
+		d.SQLMock.ExpectBegin()
+		d.SQLMock.ExpectExec("INSERT INTO `talk_records_read` .* ON DUPLICATE KEY UPDATE").
+			WillReturnResult(sqlmock.NewResult(1, affected))
+		d.SQLMock.ExpectCommit()
+	}
+
+	for i := 0; i < 2; i++ {
+		_, err := c.onRead(context.Background(), &fakeClient{}, []byte(`{"receiver_id":2,"msg_ids":["a"]}`))
+		assert.NoError(t, err)
+	}
+
+	assert.Equal(t, 2, unread.resets)
+	assert.Len(t, publisher.topics, 2)
+	assert.NoError(t, d.SQLMock.ExpectationsWereMet())
+}
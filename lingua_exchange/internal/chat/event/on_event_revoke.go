@@ -0,0 +1,23 @@
+package event
+
+import (
+	"context"
+
+	"lingua_exchange/internal/ecode"
+	"lingua_exchange/internal/types"
+	"lingua_exchange/pkg/socket"
+)
+
+// 撤回聊天消息事件
+func (c *ChatEvent) onRevoke(ctx context.Context, client socket.IClient, content []byte) (any, error) {
+	params := &types.EventTalkRevoke{}
+	if err := bind(content, params); err != nil {
+		return nil, err
+	}
+
+	if err := c.MessageService.Revoke(&ctx, client.Uid(), params.MsgId); err != nil {
+		return nil, ecode.ErrRevokeMessageError.Err(err.Error())
+	}
+
+	return nil, nil
+}
@@ -0,0 +1,143 @@
+package event
+
+import (
+	"context"
+
+	"lingua_exchange/internal/constant"
+	"lingua_exchange/internal/ecode"
+	"lingua_exchange/internal/types"
+	"lingua_exchange/pkg/socket"
+)
+
+const (
+	syncResumeLimit = 200 // 单个会话最多补发的消息数
+	syncResumeBatch = 50  // 单次查询的消息数
+)
+
+// 断线重连同步离线消息，回放完成前实时推送会被暂存
+func (c *ChatEvent) onSyncResume(ctx context.Context, client socket.IClient, content []byte) (any, error) {
+	params := &types.EventSyncResume{}
+	if err := bind(content, params); err != nil {
+		return nil, err
+	}
+
+	sessions := params.Sessions
+	if len(sessions) == 0 {
+		if params.SyncTime == 0 {
+			return nil, ecode.ErrEventParams.Err("sessions or sync_time is required")
+		}
+
+		list, err := c.syncSessions(ctx, client.Uid(), params.SyncTime)
+		if err != nil {
+			return nil, ecode.ErrSyncResume.Err(err.Error())
+		}
+
+		sessions = list
+	}
+
+	err := client.Resume(func(write func(data *socket.ClientResponse) error) error {
+		result := &types.SyncResumed{Sessions: make([]*types.SyncResumedSession, 0, len(sessions))}
+
+		for _, session := range sessions {
+			item, err := c.replay(ctx, client.Uid(), session, params.SyncTime, write)
+			if err != nil {
+				return err
+			}
+
+			if item != nil {
+				result.Sessions = append(result.Sessions, item)
+			}
+		}
+
+		return write(&socket.ClientResponse{Event: constant.PushEventSyncResumed, Content: result})
+	})
+	if err != nil {
+		return nil, ecode.ErrSyncResume.Err(err.Error())
+	}
+
+	return nil, nil
+}
+
+// syncSessions 获取全局游标之后有新消息的会话
+func (c *ChatEvent) syncSessions(ctx context.Context, uid int, since int64) ([]*types.SyncResumeSession, error) {
+	list, err := c.TalkSessionDao.List(ctx, uid)
+	if err != nil {
+		return nil, err
+	}
+
+	sessions := make([]*types.SyncResumeSession, 0)
+	for _, item := range list {
+		if item.UpdatedAt.Unix() <= since {
+			continue
+		}
+
+		sessions = append(sessions, &types.SyncResumeSession{TalkType: item.TalkType, ReceiverId: item.ReceiverId})
+	}
+
+	return sessions, nil
+}
+
+// replay 按时序补发会话中游标之后的消息，已删除的消息不会补发，无权限的群会话返回 nil
+func (c *ChatEvent) replay(ctx context.Context, uid int, session *types.SyncResumeSession, since int64, write func(data *socket.ClientResponse) error) (*types.SyncResumedSession, error) {
+	if session.TalkType == constant.ChatGroupMode {
+		err := c.PermissionService.IsAuth(ctx, &types.AuthOption{
+			TalkType:   session.TalkType,
+			UserId:     uid,
+			ReceiverId: uint64(session.ReceiverId),
+		})
+		if err != nil {
+			return nil, nil
+		}
+	}
+
+	result := &types.SyncResumedSession{
+		TalkType:   session.TalkType,
+		ReceiverId: session.ReceiverId,
+		Sequence:   session.Sequence,
+	}
+
+	for result.Count < syncResumeLimit {
+		records, err := c.TalkRecordsDao.FindAllTalkRecords(ctx, &types.FindAllTalkRecordsOpt{
+			TalkType:   session.TalkType,
+			UserId:     uid,
+			ReceiverId: session.ReceiverId,
+			Cursor:     result.Sequence,
+			Limit:      min(syncResumeBatch, syncResumeLimit-result.Count),
+			Forward:    true,
+			Since:      since,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, record := range records {
+			if record.IsRevoke == 1 {
+				record.Extra = make(map[string]any)
+			}
+
+			err := write(&socket.ClientResponse{
+				Event: constant.PushEventImMessage,
+				Content: map[string]any{
+					"sender_id":   record.UserId,
+					"receiver_id": record.ReceiverId,
+					"talk_type":   record.TalkType,
+					"data":        record,
+				},
+			})
+			if err != nil {
+				return nil, err
+			}
+
+			result.Sequence = record.Sequence
+			result.Count++
+		}
+
+		if len(records) < syncResumeBatch {
+			return result, nil
+		}
+	}
+
+	result.HasMore = true
+
+	return result, nil
+}
@@ -0,0 +1,108 @@
+package event
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"lingua_exchange/internal/constant"
+	"lingua_exchange/internal/ecode"
+	"lingua_exchange/internal/types"
+	"lingua_exchange/pkg/socket"
+	"lingua_exchange/pkg/strutil"
+	"lingua_exchange/pkg/timeutil"
+)
+
+// 会话列表，与 /api/v1/session/list 一致
+func (c *ChatEvent) onSessionList(ctx context.Context, client socket.IClient, _ []byte) (any, error) {
+	uid := client.Uid()
+
+	unReads := c.UnreadCache.All(ctx, uid)
+	if len(unReads) > 0 {
+		c.TalkSessionDao.BatchAddList(ctx, uid, unReads)
+	}
+
+	talkSessions, err := c.TalkSessionDao.List(ctx, uid)
+	if err != nil {
+		return nil, ecode.ErrServerQueryList.Err()
+	}
+
+	items := make([]*types.TalkSessionItem, 0, len(talkSessions))
+	for _, talkSession := range talkSessions {
+		value := &types.TalkSessionItem{
+			ID:         int32(talkSession.Id),
+			TalkType:   int32(talkSession.TalkType),
+			ReceiverID: int32(talkSession.ReceiverId),
+			IsTop:      int32(talkSession.IsTop),
+			IsDisturb:  int32(talkSession.IsDisturb),
+			IsRobot:    int32(talkSession.IsRobot),
+			MsgText:    "...",
+			UpdatedAt:  timeutil.FormatDatetime(talkSession.UpdatedAt),
+		}
+
+		if num, ok := unReads[fmt.Sprintf("%d_%d", talkSession.TalkType, talkSession.ReceiverId)]; ok {
+			value.UnreadNum = int32(num)
+		}
+
+		if talkSession.TalkType == constant.ChatPrivateMode {
+			value.Name = talkSession.Nickname
+			value.Avatar = talkSession.UserAvatar
+			value.IsOnline = int32(strutil.BoolToInt(c.MessageCache.IsOnline(ctx, constant.ImChannelChat, strconv.Itoa(int(value.ReceiverID)))))
+		} else {
+			value.Name = talkSession.GroupName
+			value.Avatar = talkSession.GroupAvatar
+		}
+
+		if msg, err := c.MessageCache.GetLastMessage(ctx, talkSession.TalkType, uid, talkSession.ReceiverId); err == nil {
+			value.MsgText = msg.Content
+			value.UpdatedAt = msg.Datetime
+		}
+
+		items = append(items, value)
+	}
+
+	return items, nil
+}
+
+// 聊天记录分页，与 /api/v1/session/records 一致
+func (c *ChatEvent) onTalkRecords(ctx context.Context, client socket.IClient, content []byte) (any, error) {
+	params := &types.GetTalkRecordsRequest{}
+	if err := bind(content, params); err != nil {
+		return nil, err
+	}
+
+	if params.TalkType == constant.ChatGroupMode {
+		err := c.PermissionService.IsAuth(ctx, &types.AuthOption{
+			TalkType:   params.TalkType,
+			UserId:     client.Uid(),
+			ReceiverId: uint64(params.ReceiverId),
+		})
+		if err != nil {
+			return nil, ecode.ErrGetRecordsFailed.Err("暂无权限查看群消息")
+		}
+	}
+
+	records, err := c.TalkRecordsDao.FindAllTalkRecords(ctx, &types.FindAllTalkRecordsOpt{
+		TalkType:   params.TalkType,
+		UserId:     client.Uid(),
+		ReceiverId: params.ReceiverId,
+		Cursor:     params.Cursor,
+		Limit:      params.Limit,
+	})
+	if err != nil {
+		return nil, ecode.ErrGetRecordsFailed.Err(err.Error())
+	}
+
+	cursor := 0
+	if length := len(records); length > 0 {
+		cursor = records[length-1].Sequence
+	}
+
+	for i, record := range records {
+		if record.IsRevoke == 1 {
+			records[i].Extra = make(map[string]any)
+		}
+	}
+
+	return map[string]any{"cursor": cursor, "list": records}, nil
+}
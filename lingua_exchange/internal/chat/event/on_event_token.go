@@ -0,0 +1,37 @@
+package event
+
+import (
+	"context"
+	"strconv"
+
+	"lingua_exchange/internal/ecode"
+	"lingua_exchange/internal/types"
+	"lingua_exchange/pkg/jwt"
+	"lingua_exchange/pkg/socket"
+)
+
+// onTokenRefresh 客户端通过长连接续期令牌，令牌必须属于当前连接用户
+func (c *ChatEvent) onTokenRefresh(ctx context.Context, client socket.IClient, content []byte) (any, error) {
+	var in types.EventTokenRefresh
+	if err := bind(content, &in); err != nil {
+		return nil, err
+	}
+
+	uid, err := jwt.VerifyToken(ctx, in.Token)
+	if err != nil {
+		return nil, ecode.ErrSocketTokenRefresh.Err(err.Error())
+	}
+
+	if uid != strconv.Itoa(client.Uid()) {
+		return nil, ecode.ErrSocketUnauthorized.Err()
+	}
+
+	expireAt, err := jwt.TokenExpireAt(ctx, in.Token)
+	if err != nil {
+		return nil, ecode.ErrSocketTokenRefresh.Err(err.Error())
+	}
+
+	c.tokens.watch(client, expireAt)
+
+	return map[string]any{"expire_at": expireAt.Unix()}, nil
+}
@@ -0,0 +1,94 @@
+package event
+
+import (
+	"sync"
+	"time"
+
+	"lingua_exchange/internal/constant"
+	"lingua_exchange/internal/types"
+	"lingua_exchange/pkg/socket"
+)
+
+const (
+	// 令牌过期后等待客户端续期的宽限时间
+	tokenExpiredGrace = 60 * time.Second
+
+	// 令牌过期未续期时的关闭码
+	tokenExpiredCloseCode = 4001
+)
+
+type tokenEntry struct {
+	timer *time.Timer
+}
+
+// tokenGuard 跟踪连接令牌的有效期，过期后推送 token.expired，宽限期内未续期则断开连接
+type tokenGuard struct {
+	mu      sync.Mutex
+	grace   time.Duration
+	entries map[int64]*tokenEntry
+}
+
+func newTokenGuard(grace time.Duration) *tokenGuard {
+	return &tokenGuard{grace: grace, entries: make(map[int64]*tokenEntry)}
+}
+
+// watch 设置(或重置)客户端令牌的失效时间
+func (g *tokenGuard) watch(client socket.IClient, expireAt time.Time) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.stop(client.Cid())
+
+	entry := &tokenEntry{}
+	entry.timer = time.AfterFunc(time.Until(expireAt), func() {
+		g.expired(client, entry)
+	})
+
+	g.entries[client.Cid()] = entry
+}
+
+// remove 客户端断开后清理定时器
+func (g *tokenGuard) remove(cid int64) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.stop(cid)
+}
+
+func (g *tokenGuard) stop(cid int64) {
+	if entry, ok := g.entries[cid]; ok {
+		entry.timer.Stop()
+		delete(g.entries, cid)
+	}
+}
+
+// expired 令牌过期，通知客户端并开始宽限期计时
+func (g *tokenGuard) expired(client socket.IClient, entry *tokenEntry) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	// 已续期或连接已关闭
+	if g.entries[client.Cid()] != entry {
+		return
+	}
+
+	_ = client.Write(&socket.ClientResponse{
+		Event:   constant.PushEventTokenExpired,
+		Content: &types.TokenExpired{Grace: int(g.grace / time.Second)},
+	})
+
+	grace := &tokenEntry{}
+	grace.timer = time.AfterFunc(g.grace, func() {
+		g.mu.Lock()
+		if g.entries[client.Cid()] != grace {
+			g.mu.Unlock()
+			return
+		}
+		delete(g.entries, client.Cid())
+		g.mu.Unlock()
+
+		client.Close(tokenExpiredCloseCode, "token expired")
+	})
+
+	g.entries[client.Cid()] = grace
+}
@@ -0,0 +1,706 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.34.2
+// 	protoc        (unknown)
+// source: internal/chat/payload/im.proto
+
+package payload
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	structpb "google.golang.org/protobuf/types/known/structpb"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// TalkRecord 聊天记录
+type TalkRecord struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id         int64           `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	MsgId      string          `protobuf:"bytes,2,opt,name=msg_id,json=msgId,proto3" json:"msg_id,omitempty"`
+	Sequence   int64           `protobuf:"varint,3,opt,name=sequence,proto3" json:"sequence,omitempty"`
+	TalkType   int32           `protobuf:"varint,4,opt,name=talk_type,json=talkType,proto3" json:"talk_type,omitempty"`
+	MsgType    int32           `protobuf:"varint,5,opt,name=msg_type,json=msgType,proto3" json:"msg_type,omitempty"`
+	UserId     int64           `protobuf:"varint,6,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	ReceiverId int64           `protobuf:"varint,7,opt,name=receiver_id,json=receiverId,proto3" json:"receiver_id,omitempty"`
+	Nickname   string          `protobuf:"bytes,8,opt,name=nickname,proto3" json:"nickname,omitempty"`
+	Avatar     string          `protobuf:"bytes,9,opt,name=avatar,proto3" json:"avatar,omitempty"`
+	IsRevoke   int32           `protobuf:"varint,10,opt,name=is_revoke,json=isRevoke,proto3" json:"is_revoke,omitempty"`
+	IsMark     int32           `protobuf:"varint,11,opt,name=is_mark,json=isMark,proto3" json:"is_mark,omitempty"`
+	IsRead     int32           `protobuf:"varint,12,opt,name=is_read,json=isRead,proto3" json:"is_read,omitempty"`
+	CreatedAt  string          `protobuf:"bytes,13,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	Extra      *structpb.Value `protobuf:"bytes,14,opt,name=extra,proto3" json:"extra,omitempty"` // 额外参数
+}
+
+func (x *TalkRecord) Reset() {
+	*x = TalkRecord{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_internal_chat_payload_im_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TalkRecord) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TalkRecord) ProtoMessage() {}
+
+func (x *TalkRecord) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_chat_payload_im_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TalkRecord.ProtoReflect.Descriptor instead.
+func (*TalkRecord) Descriptor() ([]byte, []int) {
+	return file_internal_chat_payload_im_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *TalkRecord) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *TalkRecord) GetMsgId() string {
+	if x != nil {
+		return x.MsgId
+	}
+	return ""
+}
+
+func (x *TalkRecord) GetSequence() int64 {
+	if x != nil {
+		return x.Sequence
+	}
+	return 0
+}
+
+func (x *TalkRecord) GetTalkType() int32 {
+	if x != nil {
+		return x.TalkType
+	}
+	return 0
+}
+
+func (x *TalkRecord) GetMsgType() int32 {
+	if x != nil {
+		return x.MsgType
+	}
+	return 0
+}
+
+func (x *TalkRecord) GetUserId() int64 {
+	if x != nil {
+		return x.UserId
+	}
+	return 0
+}
+
+func (x *TalkRecord) GetReceiverId() int64 {
+	if x != nil {
+		return x.ReceiverId
+	}
+	return 0
+}
+
+func (x *TalkRecord) GetNickname() string {
+	if x != nil {
+		return x.Nickname
+	}
+	return ""
+}
+
+func (x *TalkRecord) GetAvatar() string {
+	if x != nil {
+		return x.Avatar
+	}
+	return ""
+}
+
+func (x *TalkRecord) GetIsRevoke() int32 {
+	if x != nil {
+		return x.IsRevoke
+	}
+	return 0
+}
+
+func (x *TalkRecord) GetIsMark() int32 {
+	if x != nil {
+		return x.IsMark
+	}
+	return 0
+}
+
+func (x *TalkRecord) GetIsRead() int32 {
+	if x != nil {
+		return x.IsRead
+	}
+	return 0
+}
+
+func (x *TalkRecord) GetCreatedAt() string {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return ""
+}
+
+func (x *TalkRecord) GetExtra() *structpb.Value {
+	if x != nil {
+		return x.Extra
+	}
+	return nil
+}
+
+// ImMessage 对话消息推送
+type ImMessage struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	SenderId   int64       `protobuf:"varint,1,opt,name=sender_id,json=senderId,proto3" json:"sender_id,omitempty"`
+	ReceiverId int64       `protobuf:"varint,2,opt,name=receiver_id,json=receiverId,proto3" json:"receiver_id,omitempty"`
+	TalkType   int32       `protobuf:"varint,3,opt,name=talk_type,json=talkType,proto3" json:"talk_type,omitempty"`
+	Data       *TalkRecord `protobuf:"bytes,4,opt,name=data,proto3" json:"data,omitempty"`
+}
+
+func (x *ImMessage) Reset() {
+	*x = ImMessage{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_internal_chat_payload_im_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ImMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImMessage) ProtoMessage() {}
+
+func (x *ImMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_chat_payload_im_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImMessage.ProtoReflect.Descriptor instead.
+func (*ImMessage) Descriptor() ([]byte, []int) {
+	return file_internal_chat_payload_im_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ImMessage) GetSenderId() int64 {
+	if x != nil {
+		return x.SenderId
+	}
+	return 0
+}
+
+func (x *ImMessage) GetReceiverId() int64 {
+	if x != nil {
+		return x.ReceiverId
+	}
+	return 0
+}
+
+func (x *ImMessage) GetTalkType() int32 {
+	if x != nil {
+		return x.TalkType
+	}
+	return 0
+}
+
+func (x *ImMessage) GetData() *TalkRecord {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+// ImMessageKeyboard 键盘输入事件推送
+type ImMessageKeyboard struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	SenderId   int64 `protobuf:"varint,1,opt,name=sender_id,json=senderId,proto3" json:"sender_id,omitempty"`
+	ReceiverId int64 `protobuf:"varint,2,opt,name=receiver_id,json=receiverId,proto3" json:"receiver_id,omitempty"`
+}
+
+func (x *ImMessageKeyboard) Reset() {
+	*x = ImMessageKeyboard{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_internal_chat_payload_im_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ImMessageKeyboard) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImMessageKeyboard) ProtoMessage() {}
+
+func (x *ImMessageKeyboard) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_chat_payload_im_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImMessageKeyboard.ProtoReflect.Descriptor instead.
+func (*ImMessageKeyboard) Descriptor() ([]byte, []int) {
+	return file_internal_chat_payload_im_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ImMessageKeyboard) GetSenderId() int64 {
+	if x != nil {
+		return x.SenderId
+	}
+	return 0
+}
+
+func (x *ImMessageKeyboard) GetReceiverId() int64 {
+	if x != nil {
+		return x.ReceiverId
+	}
+	return 0
+}
+
+// ImMessageRead 对话消息读事件推送
+type ImMessageRead struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	SenderId   int64    `protobuf:"varint,1,opt,name=sender_id,json=senderId,proto3" json:"sender_id,omitempty"`
+	ReceiverId int64    `protobuf:"varint,2,opt,name=receiver_id,json=receiverId,proto3" json:"receiver_id,omitempty"`
+	MsgIds     []string `protobuf:"bytes,3,rep,name=msg_ids,json=msgIds,proto3" json:"msg_ids,omitempty"`
+}
+
+func (x *ImMessageRead) Reset() {
+	*x = ImMessageRead{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_internal_chat_payload_im_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ImMessageRead) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImMessageRead) ProtoMessage() {}
+
+func (x *ImMessageRead) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_chat_payload_im_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImMessageRead.ProtoReflect.Descriptor instead.
+func (*ImMessageRead) Descriptor() ([]byte, []int) {
+	return file_internal_chat_payload_im_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *ImMessageRead) GetSenderId() int64 {
+	if x != nil {
+		return x.SenderId
+	}
+	return 0
+}
+
+func (x *ImMessageRead) GetReceiverId() int64 {
+	if x != nil {
+		return x.ReceiverId
+	}
+	return 0
+}
+
+func (x *ImMessageRead) GetMsgIds() []string {
+	if x != nil {
+		return x.MsgIds
+	}
+	return nil
+}
+
+// ImMessageRevoke 聊天消息撤销推送
+type ImMessageRevoke struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	TalkType   int32  `protobuf:"varint,1,opt,name=talk_type,json=talkType,proto3" json:"talk_type,omitempty"`
+	SenderId   int64  `protobuf:"varint,2,opt,name=sender_id,json=senderId,proto3" json:"sender_id,omitempty"`
+	ReceiverId int64  `protobuf:"varint,3,opt,name=receiver_id,json=receiverId,proto3" json:"receiver_id,omitempty"`
+	MsgId      string `protobuf:"bytes,4,opt,name=msg_id,json=msgId,proto3" json:"msg_id,omitempty"`
+	Text       string `protobuf:"bytes,5,opt,name=text,proto3" json:"text,omitempty"`
+}
+
+func (x *ImMessageRevoke) Reset() {
+	*x = ImMessageRevoke{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_internal_chat_payload_im_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ImMessageRevoke) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImMessageRevoke) ProtoMessage() {}
+
+func (x *ImMessageRevoke) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_chat_payload_im_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImMessageRevoke.ProtoReflect.Descriptor instead.
+func (*ImMessageRevoke) Descriptor() ([]byte, []int) {
+	return file_internal_chat_payload_im_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ImMessageRevoke) GetTalkType() int32 {
+	if x != nil {
+		return x.TalkType
+	}
+	return 0
+}
+
+func (x *ImMessageRevoke) GetSenderId() int64 {
+	if x != nil {
+		return x.SenderId
+	}
+	return 0
+}
+
+func (x *ImMessageRevoke) GetReceiverId() int64 {
+	if x != nil {
+		return x.ReceiverId
+	}
+	return 0
+}
+
+func (x *ImMessageRevoke) GetMsgId() string {
+	if x != nil {
+		return x.MsgId
+	}
+	return ""
+}
+
+func (x *ImMessageRevoke) GetText() string {
+	if x != nil {
+		return x.Text
+	}
+	return ""
+}
+
+// ImGroupApply 入群申请通知
+type ImGroupApply struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	GroupName string `protobuf:"bytes,1,opt,name=group_name,json=groupName,proto3" json:"group_name,omitempty"`
+	Username  string `protobuf:"bytes,2,opt,name=username,proto3" json:"username,omitempty"`
+}
+
+func (x *ImGroupApply) Reset() {
+	*x = ImGroupApply{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_internal_chat_payload_im_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ImGroupApply) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImGroupApply) ProtoMessage() {}
+
+func (x *ImGroupApply) ProtoReflect() protoreflect.Message {
+	mi := &file_internal_chat_payload_im_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImGroupApply.ProtoReflect.Descriptor instead.
+func (*ImGroupApply) Descriptor() ([]byte, []int) {
+	return file_internal_chat_payload_im_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ImGroupApply) GetGroupName() string {
+	if x != nil {
+		return x.GroupName
+	}
+	return ""
+}
+
+func (x *ImGroupApply) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+var File_internal_chat_payload_im_proto protoreflect.FileDescriptor
+
+var file_internal_chat_payload_im_proto_rawDesc = []byte{
+	0x0a, 0x1e, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x2f, 0x63, 0x68, 0x61, 0x74, 0x2f,
+	0x70, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x2f, 0x69, 0x6d, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x12, 0x02, 0x69, 0x6d, 0x1a, 0x1c, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x73, 0x74, 0x72, 0x75, 0x63, 0x74, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x22, 0x91, 0x03, 0x0a, 0x0a, 0x54, 0x61, 0x6c, 0x6b, 0x52, 0x65, 0x63, 0x6f, 0x72,
+	0x64, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x02, 0x69,
+	0x64, 0x12, 0x15, 0x0a, 0x06, 0x6d, 0x73, 0x67, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x05, 0x6d, 0x73, 0x67, 0x49, 0x64, 0x12, 0x1a, 0x0a, 0x08, 0x73, 0x65, 0x71, 0x75,
+	0x65, 0x6e, 0x63, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x73, 0x65, 0x71, 0x75,
+	0x65, 0x6e, 0x63, 0x65, 0x12, 0x1b, 0x0a, 0x09, 0x74, 0x61, 0x6c, 0x6b, 0x5f, 0x74, 0x79, 0x70,
+	0x65, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x74, 0x61, 0x6c, 0x6b, 0x54, 0x79, 0x70,
+	0x65, 0x12, 0x19, 0x0a, 0x08, 0x6d, 0x73, 0x67, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18, 0x05, 0x20,
+	0x01, 0x28, 0x05, 0x52, 0x07, 0x6d, 0x73, 0x67, 0x54, 0x79, 0x70, 0x65, 0x12, 0x17, 0x0a, 0x07,
+	0x75, 0x73, 0x65, 0x72, 0x5f, 0x69, 0x64, 0x18, 0x06, 0x20, 0x01, 0x28, 0x03, 0x52, 0x06, 0x75,
+	0x73, 0x65, 0x72, 0x49, 0x64, 0x12, 0x1f, 0x0a, 0x0b, 0x72, 0x65, 0x63, 0x65, 0x69, 0x76, 0x65,
+	0x72, 0x5f, 0x69, 0x64, 0x18, 0x07, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0a, 0x72, 0x65, 0x63, 0x65,
+	0x69, 0x76, 0x65, 0x72, 0x49, 0x64, 0x12, 0x1a, 0x0a, 0x08, 0x6e, 0x69, 0x63, 0x6b, 0x6e, 0x61,
+	0x6d, 0x65, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x6e, 0x69, 0x63, 0x6b, 0x6e, 0x61,
+	0x6d, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x61, 0x76, 0x61, 0x74, 0x61, 0x72, 0x18, 0x09, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x06, 0x61, 0x76, 0x61, 0x74, 0x61, 0x72, 0x12, 0x1b, 0x0a, 0x09, 0x69, 0x73,
+	0x5f, 0x72, 0x65, 0x76, 0x6f, 0x6b, 0x65, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x69,
+	0x73, 0x52, 0x65, 0x76, 0x6f, 0x6b, 0x65, 0x12, 0x17, 0x0a, 0x07, 0x69, 0x73, 0x5f, 0x6d, 0x61,
+	0x72, 0x6b, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x69, 0x73, 0x4d, 0x61, 0x72, 0x6b,
+	0x12, 0x17, 0x0a, 0x07, 0x69, 0x73, 0x5f, 0x72, 0x65, 0x61, 0x64, 0x18, 0x0c, 0x20, 0x01, 0x28,
+	0x05, 0x52, 0x06, 0x69, 0x73, 0x52, 0x65, 0x61, 0x64, 0x12, 0x1d, 0x0a, 0x0a, 0x63, 0x72, 0x65,
+	0x61, 0x74, 0x65, 0x64, 0x5f, 0x61, 0x74, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x63,
+	0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x41, 0x74, 0x12, 0x2c, 0x0a, 0x05, 0x65, 0x78, 0x74, 0x72,
+	0x61, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x16, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x52,
+	0x05, 0x65, 0x78, 0x74, 0x72, 0x61, 0x22, 0x8a, 0x01, 0x0a, 0x09, 0x49, 0x6d, 0x4d, 0x65, 0x73,
+	0x73, 0x61, 0x67, 0x65, 0x12, 0x1b, 0x0a, 0x09, 0x73, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x5f, 0x69,
+	0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x73, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x49,
+	0x64, 0x12, 0x1f, 0x0a, 0x0b, 0x72, 0x65, 0x63, 0x65, 0x69, 0x76, 0x65, 0x72, 0x5f, 0x69, 0x64,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0a, 0x72, 0x65, 0x63, 0x65, 0x69, 0x76, 0x65, 0x72,
+	0x49, 0x64, 0x12, 0x1b, 0x0a, 0x09, 0x74, 0x61, 0x6c, 0x6b, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x74, 0x61, 0x6c, 0x6b, 0x54, 0x79, 0x70, 0x65, 0x12,
+	0x22, 0x0a, 0x04, 0x64, 0x61, 0x74, 0x61, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e, 0x2e,
+	0x69, 0x6d, 0x2e, 0x54, 0x61, 0x6c, 0x6b, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x52, 0x04, 0x64,
+	0x61, 0x74, 0x61, 0x22, 0x51, 0x0a, 0x11, 0x49, 0x6d, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65,
+	0x4b, 0x65, 0x79, 0x62, 0x6f, 0x61, 0x72, 0x64, 0x12, 0x1b, 0x0a, 0x09, 0x73, 0x65, 0x6e, 0x64,
+	0x65, 0x72, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x73, 0x65, 0x6e,
+	0x64, 0x65, 0x72, 0x49, 0x64, 0x12, 0x1f, 0x0a, 0x0b, 0x72, 0x65, 0x63, 0x65, 0x69, 0x76, 0x65,
+	0x72, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0a, 0x72, 0x65, 0x63, 0x65,
+	0x69, 0x76, 0x65, 0x72, 0x49, 0x64, 0x22, 0x66, 0x0a, 0x0d, 0x49, 0x6d, 0x4d, 0x65, 0x73, 0x73,
+	0x61, 0x67, 0x65, 0x52, 0x65, 0x61, 0x64, 0x12, 0x1b, 0x0a, 0x09, 0x73, 0x65, 0x6e, 0x64, 0x65,
+	0x72, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52, 0x08, 0x73, 0x65, 0x6e, 0x64,
+	0x65, 0x72, 0x49, 0x64, 0x12, 0x1f, 0x0a, 0x0b, 0x72, 0x65, 0x63, 0x65, 0x69, 0x76, 0x65, 0x72,
+	0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0a, 0x72, 0x65, 0x63, 0x65, 0x69,
+	0x76, 0x65, 0x72, 0x49, 0x64, 0x12, 0x17, 0x0a, 0x07, 0x6d, 0x73, 0x67, 0x5f, 0x69, 0x64, 0x73,
+	0x18, 0x03, 0x20, 0x03, 0x28, 0x09, 0x52, 0x06, 0x6d, 0x73, 0x67, 0x49, 0x64, 0x73, 0x22, 0x97,
+	0x01, 0x0a, 0x0f, 0x49, 0x6d, 0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x52, 0x65, 0x76, 0x6f,
+	0x6b, 0x65, 0x12, 0x1b, 0x0a, 0x09, 0x74, 0x61, 0x6c, 0x6b, 0x5f, 0x74, 0x79, 0x70, 0x65, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x74, 0x61, 0x6c, 0x6b, 0x54, 0x79, 0x70, 0x65, 0x12,
+	0x1b, 0x0a, 0x09, 0x73, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01,
+	0x28, 0x03, 0x52, 0x08, 0x73, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x49, 0x64, 0x12, 0x1f, 0x0a, 0x0b,
+	0x72, 0x65, 0x63, 0x65, 0x69, 0x76, 0x65, 0x72, 0x5f, 0x69, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28,
+	0x03, 0x52, 0x0a, 0x72, 0x65, 0x63, 0x65, 0x69, 0x76, 0x65, 0x72, 0x49, 0x64, 0x12, 0x15, 0x0a,
+	0x06, 0x6d, 0x73, 0x67, 0x5f, 0x69, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x6d,
+	0x73, 0x67, 0x49, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x74, 0x65, 0x78, 0x74, 0x18, 0x05, 0x20, 0x01,
+	0x28, 0x09, 0x52, 0x04, 0x74, 0x65, 0x78, 0x74, 0x22, 0x49, 0x0a, 0x0c, 0x49, 0x6d, 0x47, 0x72,
+	0x6f, 0x75, 0x70, 0x41, 0x70, 0x70, 0x6c, 0x79, 0x12, 0x1d, 0x0a, 0x0a, 0x67, 0x72, 0x6f, 0x75,
+	0x70, 0x5f, 0x6e, 0x61, 0x6d, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x09, 0x67, 0x72,
+	0x6f, 0x75, 0x70, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x1a, 0x0a, 0x08, 0x75, 0x73, 0x65, 0x72, 0x6e,
+	0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x75, 0x73, 0x65, 0x72, 0x6e,
+	0x61, 0x6d, 0x65, 0x42, 0x2f, 0x5a, 0x2d, 0x6c, 0x69, 0x6e, 0x67, 0x75, 0x61, 0x5f, 0x65, 0x78,
+	0x63, 0x68, 0x61, 0x6e, 0x67, 0x65, 0x2f, 0x69, 0x6e, 0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x2f,
+	0x63, 0x68, 0x61, 0x74, 0x2f, 0x70, 0x61, 0x79, 0x6c, 0x6f, 0x61, 0x64, 0x3b, 0x70, 0x61, 0x79,
+	0x6c, 0x6f, 0x61, 0x64, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_internal_chat_payload_im_proto_rawDescOnce sync.Once
+	file_internal_chat_payload_im_proto_rawDescData = file_internal_chat_payload_im_proto_rawDesc
+)
+
+func file_internal_chat_payload_im_proto_rawDescGZIP() []byte {
+	file_internal_chat_payload_im_proto_rawDescOnce.Do(func() {
+		file_internal_chat_payload_im_proto_rawDescData = protoimpl.X.CompressGZIP(file_internal_chat_payload_im_proto_rawDescData)
+	})
+	return file_internal_chat_payload_im_proto_rawDescData
+}
+
+var file_internal_chat_payload_im_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
+var file_internal_chat_payload_im_proto_goTypes = []any{
+	(*TalkRecord)(nil),        // 0: im.TalkRecord
+	(*ImMessage)(nil),         // 1: im.ImMessage
+	(*ImMessageKeyboard)(nil), // 2: im.ImMessageKeyboard
+	(*ImMessageRead)(nil),     // 3: im.ImMessageRead
+	(*ImMessageRevoke)(nil),   // 4: im.ImMessageRevoke
+	(*ImGroupApply)(nil),      // 5: im.ImGroupApply
+	(*structpb.Value)(nil),    // 6: google.protobuf.Value
+}
+var file_internal_chat_payload_im_proto_depIdxs = []int32{
+	6, // 0: im.TalkRecord.extra:type_name -> google.protobuf.Value
+	0, // 1: im.ImMessage.data:type_name -> im.TalkRecord
+	2, // [2:2] is the sub-list for method output_type
+	2, // [2:2] is the sub-list for method input_type
+	2, // [2:2] is the sub-list for extension type_name
+	2, // [2:2] is the sub-list for extension extendee
+	0, // [0:2] is the sub-list for field type_name
+}
+
+func init() { file_internal_chat_payload_im_proto_init() }
+func file_internal_chat_payload_im_proto_init() {
+	if File_internal_chat_payload_im_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_internal_chat_payload_im_proto_msgTypes[0].Exporter = func(v any, i int) any {
+			switch v := v.(*TalkRecord); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_internal_chat_payload_im_proto_msgTypes[1].Exporter = func(v any, i int) any {
+			switch v := v.(*ImMessage); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_internal_chat_payload_im_proto_msgTypes[2].Exporter = func(v any, i int) any {
+			switch v := v.(*ImMessageKeyboard); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_internal_chat_payload_im_proto_msgTypes[3].Exporter = func(v any, i int) any {
+			switch v := v.(*ImMessageRead); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_internal_chat_payload_im_proto_msgTypes[4].Exporter = func(v any, i int) any {
+			switch v := v.(*ImMessageRevoke); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_internal_chat_payload_im_proto_msgTypes[5].Exporter = func(v any, i int) any {
+			switch v := v.(*ImGroupApply); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_internal_chat_payload_im_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   6,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_internal_chat_payload_im_proto_goTypes,
+		DependencyIndexes: file_internal_chat_payload_im_proto_depIdxs,
+		MessageInfos:      file_internal_chat_payload_im_proto_msgTypes,
+	}.Build()
+	File_internal_chat_payload_im_proto = out.File
+	file_internal_chat_payload_im_proto_rawDesc = nil
+	file_internal_chat_payload_im_proto_goTypes = nil
+	file_internal_chat_payload_im_proto_depIdxs = nil
+}
@@ -0,0 +1,26 @@
+// Package payload 下行事件内容的 Protobuf 类型定义，im.pb.go 由 im.proto 生成，修改 im.proto 后执行 go generate 重新生成
+package payload
+
+//go:generate protoc -I ../../.. --go_out=../../.. --go_opt=paths=source_relative ../../../internal/chat/payload/im.proto
+
+import (
+	"google.golang.org/protobuf/proto"
+	"lingua_exchange/internal/constant"
+	"lingua_exchange/pkg/socket"
+)
+
+// events 下行事件对应的消息类型
+var events = map[string]proto.Message{
+	constant.PushEventImMessage:         (*ImMessage)(nil),
+	constant.PushEventImMessageKeyboard: (*ImMessageKeyboard)(nil),
+	constant.PushEventImMessageRead:     (*ImMessageRead)(nil),
+	constant.PushEventImMessageRevoke:   (*ImMessageRevoke)(nil),
+	constant.PushEventGroupApply:        (*ImGroupApply)(nil),
+}
+
+// Register 注册下行事件内容类型，供 Protobuf 编码的客户端使用
+func Register() {
+	for event, msg := range events {
+		socket.RegisterProtoPayload(event, msg.ProtoReflect().Descriptor())
+	}
+}
@@ -0,0 +1,55 @@
+package payload
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+	"lingua_exchange/internal/constant"
+	"lingua_exchange/internal/types"
+	"lingua_exchange/pkg/socket"
+)
+
+func TestRegister(t *testing.T) {
+	Register()
+
+	codec, _ := socket.Codec(socket.CodecProtobuf)
+	bt, err := codec.Marshal(&socket.ClientResponse{
+		Sid:   "a1b2c3",
+		Event: constant.PushEventImMessage,
+		Content: map[string]any{
+			"sender_id":   2054,
+			"receiver_id": 10001,
+			"talk_type":   2,
+			"data": &types.TalkRecordItem{
+				MsgId:  "7c0e4d2a",
+				UserId: 2054,
+				Extra:  map[string]any{"content": "hallo"},
+			},
+		},
+	})
+	assert.NoError(t, err)
+
+	// 事件内容按 ImMessage 类型编码到 payload 字段
+	var payload []byte
+	for len(bt) > 0 {
+		number, _, n := protowire.ConsumeTag(bt)
+		assert.Greater(t, n, 0)
+
+		val, m := protowire.ConsumeBytes(bt[n:])
+		if number == 4 {
+			payload = val
+		}
+		bt = bt[n+m:]
+	}
+
+	msg := &ImMessage{}
+	assert.NoError(t, proto.Unmarshal(payload, msg))
+	assert.Equal(t, "hallo", msg.Data.Extra.GetStructValue().Fields["content"].GetStringValue())
+
+	out, err := protojson.MarshalOptions{UseProtoNames: true}.Marshal(msg)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"sender_id":"2054","receiver_id":"10001","talk_type":2,"data":{"msg_id":"7c0e4d2a","user_id":"2054","extra":{"content":"hallo"}}}`, string(out))
+}
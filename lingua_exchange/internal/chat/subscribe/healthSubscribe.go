@@ -10,17 +10,21 @@ import (
 	"lingua_exchange/internal/cache"
 	"lingua_exchange/internal/config"
 	"lingua_exchange/internal/model"
+	"lingua_exchange/pkg/socket"
 )
 
+// HealthSubscribe 定时上报节点心跳及各渠道连接状态
 type HealthSubscribe struct {
 	config      *config.Config
 	serverCache cache.ServerCache
+	startedAt   int64
 }
 
 func NewHealthSubscribe() *HealthSubscribe {
 	return &HealthSubscribe{
 		config:      config.Get(),
 		serverCache: cache.NewServerCache(model.GetCacheType()),
+		startedAt:   time.Now().Unix(),
 	}
 }
 
@@ -33,9 +37,29 @@ func (s *HealthSubscribe) Setup(ctx context.Context) error {
 		case <-ctx.Done():
 			return nil
 		case <-time.After(10 * time.Second):
-			if err := s.serverCache.Set(ctx, s.config.App.Sid, time.Now().Unix()); err != nil {
-				logger.Error(fmt.Sprintf("Websocket HealthSubscribe Report Err: %s", err.Error()))
-			}
+			s.report(ctx)
 		}
 	}
 }
+
+// report 上报心跳时间及运行状态
+func (s *HealthSubscribe) report(ctx context.Context) {
+	now := time.Now().Unix()
+	if err := s.serverCache.Set(ctx, s.config.App.Sid, now); err != nil {
+		logger.Error(fmt.Sprintf("Websocket HealthSubscribe Report Err: %s", err.Error()))
+		return
+	}
+
+	stats := &cache.ServerStats{StartedAt: s.startedAt, ReportedAt: now, Channels: make([]*cache.ChannelStats, 0)}
+	for _, ch := range socket.Session.Channels() {
+		stats.Channels = append(stats.Channels, &cache.ChannelStats{
+			Name:        ch.Name(),
+			Connections: ch.Count(),
+			Pending:     ch.Pending(),
+		})
+	}
+
+	if err := s.serverCache.SetStats(ctx, s.config.App.Sid, stats); err != nil {
+		logger.Error(fmt.Sprintf("Websocket HealthSubscribe Report Stats Err: %s", err.Error()))
+	}
+}
@@ -0,0 +1,172 @@
+package subscribe
+
+import (
+	"context"
+	"log"
+	"slices"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/zhufuyi/sponge/pkg/logger"
+	"lingua_exchange/internal/bus"
+	"lingua_exchange/internal/cache"
+	"lingua_exchange/internal/config"
+	"lingua_exchange/internal/model"
+)
+
+const (
+	janitorLock     = "server-janitor" // 清理任务锁名称，多节点部署时同一时刻只有一个节点执行
+	janitorLockTtl  = 300              // 锁有效期(秒)
+	janitorInterval = time.Minute      // 检测间隔
+	janitorBatch    = 500              // 单次扫描及删除的键数量
+)
+
+const (
+	janitorResultCleaned = "cleaned" // 已清理
+	janitorResultRevived = "revived" // 清理期间恢复心跳，终止清理
+	janitorResultFailure = "failure" // 清理失败
+)
+
+var (
+	janitorServerCounter = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "im",
+		Subsystem: "janitor",
+		Name:      "servers_total",
+		Help:      "Total number of dead servers processed by result.",
+	}, []string{"result"})
+
+	janitorKeysCounter = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: "im",
+		Subsystem: "janitor",
+		Name:      "keys_deleted_total",
+		Help:      "Total number of connection state keys deleted for dead servers.",
+	})
+)
+
+// JanitorSubscribe 定时清理心跳超时节点在 Redis 中遗留的客户端、用户及房间连接状态，以及消息总线中的消费组
+type JanitorSubscribe struct {
+	config      *config.Config
+	serverCache cache.ServerCache
+	lock        *cache.RedisLock
+	purger      bus.Purger // 消息总线不保存节点订阅状态时为 nil
+}
+
+func NewJanitorSubscribe() *JanitorSubscribe {
+	purger, _ := bus.Get().(bus.Purger)
+
+	return &JanitorSubscribe{
+		config:      config.Get(),
+		serverCache: cache.NewServerCache(model.GetCacheType()),
+		lock:        cache.NewRedisLock(model.GetRedisCli()),
+		purger:      purger,
+	}
+}
+
+func (s *JanitorSubscribe) Setup(ctx context.Context) error {
+
+	log.Println("Start JanitorSubscribe")
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(janitorInterval):
+			s.clean(ctx)
+		}
+	}
+}
+
+// clean 标记心跳超时的节点并清理其连接状态，上次未清理完的节点继续清理
+func (s *JanitorSubscribe) clean(ctx context.Context) {
+	if !s.lock.Lock(ctx, janitorLock, janitorLockTtl) {
+		return
+	}
+	defer s.lock.UnLock(ctx, janitorLock)
+
+	servers := s.serverCache.All(ctx, 2)
+	for _, server := range s.serverCache.GetExpireServerAll(ctx) {
+		if !slices.Contains(servers, server) {
+			servers = append(servers, server)
+		}
+	}
+
+	for _, server := range servers {
+		if server == s.config.App.Sid {
+			continue
+		}
+
+		if err := s.serverCache.SetExpireServer(ctx, server); err != nil {
+			logger.Errorf("JanitorSubscribe mark server %s expired err: %s", server, err.Error())
+			continue
+		}
+
+		result := s.purge(ctx, server)
+		janitorServerCounter.WithLabelValues(result).Inc()
+	}
+}
+
+// purge 分批删除节点的连接状态键，每批删除前确认节点仍未恢复心跳
+func (s *JanitorSubscribe) purge(ctx context.Context, server string) string {
+	var (
+		cursor uint64
+		total  int64
+	)
+
+	for {
+		if !s.expired(ctx, server) {
+			logger.Infof("JanitorSubscribe server %s revived, %d keys deleted", server, total)
+			return janitorResultRevived
+		}
+
+		keys, next, err := s.serverCache.ScanServerKeys(ctx, server, cursor, janitorBatch)
+		if err != nil {
+			logger.Errorf("JanitorSubscribe scan server %s keys err: %s", server, err.Error())
+			return janitorResultFailure
+		}
+
+		n, err := s.serverCache.DelKeys(ctx, keys)
+		if err != nil {
+			logger.Errorf("JanitorSubscribe delete server %s keys err: %s", server, err.Error())
+			return janitorResultFailure
+		}
+
+		total += n
+		janitorKeysCounter.Add(float64(n))
+
+		if cursor = next; cursor == 0 {
+			break
+		}
+	}
+
+	if s.purger != nil {
+		if err := s.purger.Purge(ctx, server); err != nil {
+			logger.Errorf("JanitorSubscribe purge server %s bus err: %s", server, err.Error())
+			return janitorResultFailure
+		}
+	}
+
+	if err := s.serverCache.Del(ctx, server); err != nil {
+		logger.Errorf("JanitorSubscribe delete server %s err: %s", server, err.Error())
+		return janitorResultFailure
+	}
+
+	if err := s.serverCache.DelExpireServer(ctx, server); err != nil {
+		logger.Errorf("JanitorSubscribe delete expired server %s err: %s", server, err.Error())
+		return janitorResultFailure
+	}
+
+	logger.Infof("JanitorSubscribe server %s cleaned, %d keys deleted", server, total)
+
+	return janitorResultCleaned
+}
+
+// expired 节点心跳是否超时，已从心跳列表移除的视为超时
+func (s *JanitorSubscribe) expired(ctx context.Context, server string) bool {
+	last, err := s.serverCache.LastHeartbeat(ctx, server)
+	if err != nil {
+		return false
+	}
+
+	return time.Now().Unix()-last >= cache.ServerOverTime
+}
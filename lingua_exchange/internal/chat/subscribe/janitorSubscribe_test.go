@@ -0,0 +1,187 @@
+package subscribe
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"lingua_exchange/internal/cache"
+	"lingua_exchange/internal/config"
+)
+
+// fakeServerCache 内存实现的服务节点缓存，扫描时每批返回一个键
+type fakeServerCache struct {
+	mu        sync.Mutex
+	servers   map[string]int64
+	expired   map[string]bool
+	keys      map[string]bool
+	onDelKeys func()
+}
+
+func newFakeServerCache() *fakeServerCache {
+	return &fakeServerCache{servers: map[string]int64{}, expired: map[string]bool{}, keys: map[string]bool{}}
+}
+
+func (f *fakeServerCache) Set(ctx context.Context, server string, time int64) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	delete(f.expired, server)
+	f.servers[server] = time
+	return nil
+}
+
+func (f *fakeServerCache) GetExpireServerAll(ctx context.Context) []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	items := make([]string, 0)
+	for server := range f.expired {
+		items = append(items, server)
+	}
+	return items
+}
+
+func (f *fakeServerCache) Del(ctx context.Context, server string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	delete(f.servers, server)
+	return nil
+}
+
+func (f *fakeServerCache) All(ctx context.Context, status int) []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	items := make([]string, 0)
+	for server, last := range f.servers {
+		if status == 2 && time.Now().Unix()-last >= cache.ServerOverTime {
+			items = append(items, server)
+		}
+	}
+	return items
+}
+
+func (f *fakeServerCache) SetExpireServer(ctx context.Context, server string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.expired[server] = true
+	return nil
+}
+
+func (f *fakeServerCache) DelExpireServer(ctx context.Context, server string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	delete(f.expired, server)
+	return nil
+}
+
+func (f *fakeServerCache) LastHeartbeat(ctx context.Context, server string) (int64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.servers[server], nil
+}
+
+func (f *fakeServerCache) ScanServerKeys(ctx context.Context, server string, cursor uint64, count int64) ([]string, uint64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for key := range f.keys {
+		if strings.HasPrefix(key, "ws:"+server+":") {
+			return []string{key}, cursor + 1, nil
+		}
+	}
+	return nil, 0, nil
+}
+
+func (f *fakeServerCache) DelKeys(ctx context.Context, keys []string) (int64, error) {
+	f.mu.Lock()
+	for _, key := range keys {
+		delete(f.keys, key)
+	}
+	f.mu.Unlock()
+
+	if f.onDelKeys != nil {
+		f.onDelKeys()
+	}
+	return int64(len(keys)), nil
+}
+
+func (f *fakeServerCache) SetStats(ctx context.Context, server string, stats *cache.ServerStats) error {
+	return nil
+}
+
+func (f *fakeServerCache) GetStats(ctx context.Context, servers []string) (map[string]*cache.ServerStats, error) {
+	return map[string]*cache.ServerStats{}, nil
+}
+
+// fakePurger 记录清理过消息总线订阅状态的节点
+type fakePurger struct {
+	servers []string
+}
+
+func (f *fakePurger) Purge(ctx context.Context, sid string) error {
+	f.servers = append(f.servers, sid)
+	return nil
+}
+
+func TestJanitorSubscribe_Clean(t *testing.T) {
+	mr := miniredis.RunT(t)
+	rds := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	defer rds.Close()
+
+	ctx := context.Background()
+	stale := time.Now().Unix() - cache.ServerOverTime - 10
+
+	servers := newFakeServerCache()
+	_ = servers.Set(ctx, "self", stale)
+	_ = servers.Set(ctx, "alive", time.Now().Unix())
+	_ = servers.Set(ctx, "dead", stale)
+	_ = servers.Set(ctx, "revived", stale)
+	for _, key := range []string{"ws:self:chat:client", "ws:alive:chat:client", "ws:dead:chat:client", "ws:dead:chat:user:1", "ws:dead:room_chat_group:1", "ws:revived:chat:client", "ws:revived:chat:user:1"} {
+		servers.keys[key] = true
+	}
+
+	// revived 节点在第一批删除后恢复心跳
+	servers.onDelKeys = func() {
+		if !servers.keys["ws:revived:chat:client"] || !servers.keys["ws:revived:chat:user:1"] {
+			_ = servers.Set(ctx, "revived", time.Now().Unix())
+		}
+	}
+
+	s := &JanitorSubscribe{
+		config:      &config.Config{App: config.App{Sid: "self"}},
+		serverCache: servers,
+		lock:        cache.NewRedisLock(rds),
+		purger:      &fakePurger{},
+	}
+
+	s.clean(ctx)
+
+	// dead 全部清理，revived 恢复心跳后保留剩余的键
+	remains := make([]string, 0)
+	for key := range servers.keys {
+		remains = append(remains, strings.Join(strings.Split(key, ":")[:2], ":"))
+	}
+	assert.ElementsMatch(t, []string{"ws:self", "ws:alive", "ws:revived"}, remains)
+	assert.NotContains(t, servers.servers, "dead")
+	assert.Contains(t, servers.servers, "revived")
+	assert.Empty(t, servers.GetExpireServerAll(ctx))
+	assert.Equal(t, []string{"dead"}, s.purger.(*fakePurger).servers)
+
+	// 其它节点持有锁时跳过
+	servers.keys["ws:dead:chat:client"] = true
+	_ = servers.SetExpireServer(ctx, "dead")
+	assert.True(t, s.lock.Lock(ctx, janitorLock, janitorLockTtl))
+	s.clean(ctx)
+	assert.True(t, servers.keys["ws:dead:chat:client"])
+}
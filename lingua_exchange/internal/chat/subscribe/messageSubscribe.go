@@ -2,71 +2,72 @@ package subscribe
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
-	"log"
 	"time"
 
-	"github.com/redis/go-redis/v9"
-	"github.com/sourcegraph/conc/pool"
 	"github.com/zhufuyi/sponge/pkg/logger"
+	"golang.org/x/sync/errgroup"
+	"lingua_exchange/internal/bus"
 	"lingua_exchange/internal/chat/consume"
 	"lingua_exchange/internal/config"
 	"lingua_exchange/internal/constant"
 	"lingua_exchange/internal/model"
 	"lingua_exchange/internal/types"
-	"lingua_exchange/pkg/utils"
+	"lingua_exchange/pkg/socket"
 )
 
+// 单条订阅消息的最长处理时间
+const consumeTimeout = 30 * time.Second
+
 type MessageSubscribe struct {
 	config  *config.Config
-	redis   *redis.Client
-	consume *consume.IMConsumer
+	bus     bus.Subscriber
+	consume IConsume // 默认 chat 渠道消费者，其它渠道使用 ChannelHandler
 }
 
 func NewMessageSubscribe() *MessageSubscribe {
 	return &MessageSubscribe{
 		config:  config.Get(),
-		redis:   model.GetRedisCli(),
-		consume: consume.NewIMConsumer(),
+		bus:     bus.Get(),
+		consume: consume.NewIMHandler(model.GetDB(), model.GetRedisCli(), config.Get(), model.GetCacheType()),
 	}
 }
 
-func (m *MessageSubscribe) SetUp(ctx context.Context) error {
-	logger.Info("start subscribing message")
+// Setup 订阅所有渠道的消息，运行期间注册的渠道在注册时订阅
+func (m *MessageSubscribe) Setup(ctx context.Context) error {
+	logger.Info("start subscribing message", logger.String("transport", m.config.App.MessageTransport))
 
-	go m.subscribe(ctx, []string{constant.ImTopicChat, fmt.Sprintf(constant.ImTopicChatPrivate, m.config.App.Sid)}, m.consume)
-	<-ctx.Done()
-	return nil
-}
+	eg, ctx := errgroup.WithContext(ctx)
 
-func (m *MessageSubscribe) subscribe(ctx context.Context, topic []string, consume IConsume) {
-	sub := m.redis.Subscribe(ctx, topic...)
-	defer sub.Close()
+	socket.Session.Watch(func(ch *socket.Channel) {
+		topics := []string{
+			fmt.Sprintf(constant.ImTopicChannel, ch.Name()),
+			fmt.Sprintf(constant.ImTopicChannelPrivate, ch.Name(), m.config.App.Sid),
+		}
 
-	worker := pool.New().WithMaxGoroutines(10)
+		var consume IConsume = consume.NewChannelHandler(ch)
+		if ch.Name() == constant.ImChannelChat {
+			consume = m.consume
+		}
 
-	for data := range sub.Channel(redis.WithChannelHealthCheckInterval(10 * time.Second)) {
-		m.handle(worker, data, consume)
-	}
+		eg.Go(func() error {
+			return m.bus.Subscribe(ctx, topics, m.handle(ch.Name(), consume))
+		})
+	})
 
-	worker.Wait()
+	return eg.Wait()
 }
 
-func (m *MessageSubscribe) handle(worker *pool.Pool, data *redis.Message, consume IConsume) {
-	worker.Go(func() {
-		var in types.SubscribeContent
-		if err := json.Unmarshal([]byte(data.Payload), &in); err != nil {
-			log.Println("SubscribeContent Unmarshal Err: ", err.Error())
-			return
-		}
+func (m *MessageSubscribe) handle(channel string, consume IConsume) bus.Handler {
+	return func(ctx context.Context, msg *types.SubscribeContent) error {
+		ctx, cancel := context.WithTimeout(ctx, consumeTimeout)
+		defer cancel()
 
-		defer func() {
-			if err := recover(); err != nil {
-				log.Println("MessageSubscribe Call Err: ", utils.PanicTrace(err))
-			}
-		}()
+		if err := consume.Call(ctx, msg.Event, []byte(msg.Data)); err != nil {
+			logger.Error("MessageSubscribe Call Err", logger.String("channel", channel), logger.String("event", msg.Event), logger.Err(err))
+			return err
+		}
 
-		consume.Call(in.Event, []byte(in.Data))
-	})
+		return nil
+	}
 }
@@ -0,0 +1,190 @@
+package subscribe
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/bytedance/sonic"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/sync/errgroup"
+	"lingua_exchange/internal/bus"
+	"lingua_exchange/internal/cache"
+	"lingua_exchange/internal/chat/consume"
+	"lingua_exchange/internal/config"
+	"lingua_exchange/internal/constant"
+	"lingua_exchange/internal/model"
+	"lingua_exchange/internal/types"
+	"lingua_exchange/pkg/socket"
+)
+
+// fakeConn 模拟客户端连接，记录服务端推送的数据
+type fakeConn struct {
+	once   sync.Once
+	closed chan struct{}
+	frames chan []byte
+}
+
+func newFakeConn() *fakeConn {
+	return &fakeConn{closed: make(chan struct{}), frames: make(chan []byte, 100)}
+}
+
+func (f *fakeConn) Read() ([]byte, error) {
+	<-f.closed
+	return nil, errors.New("connection closed")
+}
+
+func (f *fakeConn) Write(bytes []byte) error {
+	f.frames <- bytes
+	return nil
+}
+
+func (f *fakeConn) Close() error {
+	f.once.Do(func() { close(f.closed) })
+	return nil
+}
+
+func (f *fakeConn) SetCloseHandler(fn func(code int, text string) error) {}
+
+func (f *fakeConn) SetWriteDeadline(t time.Time) error { return nil }
+
+func (f *fakeConn) Network() string {
+	return "fake"
+}
+
+// waitEvent 等待指定事件的推送
+func (f *fakeConn) waitEvent(t *testing.T, event string) []byte {
+	timeout := time.After(10 * time.Second)
+	for {
+		select {
+		case frame := <-f.frames:
+			val, err := sonic.Get(frame, "event")
+			require.NoError(t, err)
+			if name, _ := val.String(); name == event {
+				return frame
+			}
+		case <-timeout:
+			t.Fatalf("wait event %s timeout", event)
+			return nil
+		}
+	}
+}
+
+func TestMessageSubscribe_EndToEnd(t *testing.T) {
+	mr := miniredis.RunT(t)
+
+	config.Set(&config.Config{
+		App:   config.App{Sid: "sid-test", CacheType: "redis"},
+		Redis: config.Redis{Dsn: mr.Addr()},
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	eg, groupCtx := errgroup.WithContext(ctx)
+	socket.Initialize(groupCtx, eg, func(name string) {}, nil)
+
+	rdb := model.GetRedisCli()
+	conf := config.Get()
+
+	conn := newFakeConn()
+	defer conn.Close()
+
+	err := socket.NewClient(conn, &socket.ClientOption{
+		Uid:     2,
+		Channel: socket.Session.Chat,
+		Storage: cache.NewMessageCache(model.GetCacheType()),
+	}, socket.NewEvent())
+	require.NoError(t, err)
+	conn.waitEvent(t, "connect")
+
+	privateTopic := fmt.Sprintf(constant.ImTopicChatPrivate, conf.App.Sid)
+
+	// 依次验证各消息总线，ready 用于等待订阅建立
+	memory := bus.NewMemory()
+	cases := []struct {
+		transport string
+		bus       bus.Bus
+		ready     func() bool
+	}{
+		{
+			transport: constant.ImTransportPubSub,
+			bus:       bus.NewRedisPubSub(rdb),
+			ready: func() bool {
+				n := mr.PubSubNumSub(constant.ImTopicChat, privateTopic)
+				return n[constant.ImTopicChat] == 1 && n[privateTopic] == 1
+			},
+		},
+		{
+			transport: constant.ImTransportStream,
+			bus:       bus.NewRedisStream(rdb, conf.App.Sid, 1000),
+			ready: func() bool {
+				groups, err := rdb.XInfoGroups(ctx, privateTopic).Result()
+				return err == nil && len(groups) == 1
+			},
+		},
+		{
+			transport: constant.ImTransportMemory,
+			bus:       memory,
+			ready: func() bool {
+				time.Sleep(100 * time.Millisecond)
+				return true
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.transport, func(t *testing.T) {
+			subCtx, subCancel := context.WithCancel(ctx)
+			defer subCancel()
+
+			sub := &MessageSubscribe{
+				config:  conf,
+				bus:     c.bus,
+				consume: consume.NewIMHandler(nil, rdb, conf, model.GetCacheType()),
+			}
+			go func() { _ = sub.Setup(subCtx) }()
+
+			require.Eventually(t, c.ready, 5*time.Second, 50*time.Millisecond)
+
+			// 未注册事件及非法数据不影响后续消息的消费
+			publish := func(topic string, msg *types.SubscribeContent) {
+				require.NoError(t, c.bus.Publish(ctx, topic, msg))
+			}
+
+			// 未注册事件、非法数据及高版本消息不影响后续消息的消费
+			publish(constant.ImTopicChat, bus.NewMessage("sub.im.unknown", map[string]any{}))
+			publish(constant.ImTopicChat, &types.SubscribeContent{Event: constant.SubEventImMessageKeyboard, Data: "{"})
+			publish(constant.ImTopicChat, &types.SubscribeContent{Version: types.SubscribeContentVersion + 1, Event: constant.SubEventImMessageKeyboard, Data: "{"})
+
+			publish(constant.ImTopicChat, bus.NewMessage(constant.SubEventImMessageKeyboard, &types.ConsumeTalkKeyboard{SenderID: 1, ReceiverID: 2}))
+			frame := conn.waitEvent(t, constant.PushEventImMessageKeyboard)
+			val, err := sonic.Get(frame, "content", "sender_id")
+			require.NoError(t, err)
+			senderId, _ := val.Int64()
+			assert.Equal(t, int64(1), senderId)
+
+			publish(privateTopic, bus.NewMessage(constant.SubEventImMessageRead, &types.ConsumeTalkRead{SenderId: 1, ReceiverId: 2, MsgIds: []string{"msg-1"}}))
+			frame = conn.waitEvent(t, constant.PushEventImMessageRead)
+			val, err = sonic.Get(frame, "content", "msg_ids", 0)
+			require.NoError(t, err)
+			msgId, _ := val.String()
+			assert.Equal(t, "msg-1", msgId)
+		})
+	}
+
+	// Stream 消息处理成功后需要确认，未注册及处理失败的消息保留在待确认列表中等待回收
+	require.Eventually(t, func() bool {
+		pending, err := rdb.XPending(ctx, privateTopic, conf.App.Sid).Result()
+		return err == nil && pending.Count == 0
+	}, 5*time.Second, 50*time.Millisecond)
+
+	pending, err := rdb.XPending(ctx, constant.ImTopicChat, conf.App.Sid).Result()
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), pending.Count)
+}
@@ -0,0 +1,53 @@
+package subscribe
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/zhufuyi/sponge/pkg/logger"
+	"lingua_exchange/internal/cache"
+	"lingua_exchange/internal/imService"
+	"lingua_exchange/internal/model"
+)
+
+// 补偿投递任务锁名称，多节点部署时同一时刻只有一个节点执行
+const outboxRelayLock = "outbox-relay"
+
+// OutboxSubscribe 定时补偿投递发件箱中未推送的消息
+type OutboxSubscribe struct {
+	outbox imService.IOutboxService
+	lock   *cache.RedisLock
+}
+
+func NewOutboxSubscribe() *OutboxSubscribe {
+	return &OutboxSubscribe{
+		outbox: imService.NewOutboxService(),
+		lock:   cache.NewRedisLock(model.GetRedisCli()),
+	}
+}
+
+func (s *OutboxSubscribe) Setup(ctx context.Context) error {
+
+	log.Println("Start OutboxSubscribe")
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(5 * time.Second):
+			s.relay(ctx)
+		}
+	}
+}
+
+func (s *OutboxSubscribe) relay(ctx context.Context) {
+	if !s.lock.Lock(ctx, outboxRelayLock, 30) {
+		return
+	}
+	defer s.lock.UnLock(ctx, outboxRelayLock)
+
+	if err := s.outbox.Relay(ctx); err != nil {
+		logger.Errorf("OutboxSubscribe relay err: %s", err.Error())
+	}
+}
@@ -17,18 +17,22 @@ type ISubscriberServer interface {
 
 // IConsume 消费
 type IConsume interface {
-	Call(event string, data []byte)
+	Call(ctx context.Context, event string, data []byte) error
 }
 
 type SubscriberServers struct {
 	HealthSubscribe  *HealthSubscribe  // 注册健康上报
 	MessageSubscribe *MessageSubscribe // 注册消息订阅
+	OutboxSubscribe  *OutboxSubscribe  // 注册发件箱补偿投递
+	JanitorSubscribe *JanitorSubscribe // 注册失效节点连接状态清理
 }
 
 func NewSubscriberServers() *SubscriberServers {
 	return &SubscriberServers{
 		HealthSubscribe:  NewHealthSubscribe(),
 		MessageSubscribe: NewMessageSubscribe(),
+		OutboxSubscribe:  NewOutboxSubscribe(),
+		JanitorSubscribe: NewJanitorSubscribe(),
 	}
 }
 
@@ -37,9 +37,15 @@ type Config struct {
 	Jaeger   Jaeger   `yaml:"jaeger" json:"jaeger"`
 	Logger   Logger   `yaml:"logger" json:"logger"`
 	NacosRd  NacosRd  `yaml:"nacosRd" json:"nacosRd"`
+	Nats     Nats     `yaml:"nats" json:"nats"`
 	Redis    Redis    `yaml:"redis" json:"redis"`
 	Server   Server   `yaml:"server" json:"server"`
 	SMTP     SMTP     `yaml:"smtp" json:"smtp"`
+	Socket   Socket   `yaml:"socket" json:"socket"`
+}
+
+type Nats struct {
+	URL string `yaml:"url" json:"url"`
 }
 
 type Consul struct {
@@ -73,9 +79,11 @@ type App struct {
 	EnableTrace           bool    `yaml:"enableTrace" json:"enableTrace"`
 	Env                   string  `yaml:"env" json:"env"`
 	Host                  string  `yaml:"host" json:"host"`
+	MessageTransport      string  `yaml:"messageTransport" json:"messageTransport"`
 	Name                  string  `yaml:"name" json:"name"`
 	RegistryDiscoveryType string  `yaml:"registryDiscoveryType" json:"registryDiscoveryType"`
 	Sid                   string  `yaml:"sid" json:"sid"`
+	StreamMaxLen          int64   `yaml:"streamMaxLen" json:"streamMaxLen"`
 	TracingSamplingRate   float64 `yaml:"tracingSamplingRate" json:"tracingSamplingRate"`
 	Version               string  `yaml:"version" json:"version"`
 }
@@ -117,6 +125,66 @@ type HTTP struct {
 	Timeout int `yaml:"timeout" json:"timeout"`
 }
 
+type Socket struct {
+	AdminToken     string                   `yaml:"adminToken" json:"adminToken"`
+	Buffer         int                      `yaml:"buffer" json:"buffer"`
+	Channels       map[string]SocketChannel `yaml:"channels" json:"channels"`
+	DevicePolicy   SocketDevicePolicy       `yaml:"devicePolicy" json:"devicePolicy"`
+	MaxOverflow    int                      `yaml:"maxOverflow" json:"maxOverflow"`
+	OverflowPolicy string                   `yaml:"overflowPolicy" json:"overflowPolicy"`
+	RateLimit      SocketRateLimit          `yaml:"rateLimit" json:"rateLimit"`
+	Websocket      SocketWebsocket          `yaml:"websocket" json:"websocket"`
+	WriteTimeout   int                      `yaml:"writeTimeout" json:"writeTimeout"`
+}
+
+type SocketDevicePolicy struct {
+	Enable bool                `yaml:"enable" json:"enable"`
+	Groups []SocketDeviceGroup `yaml:"groups" json:"groups"`
+}
+
+type SocketDeviceGroup struct {
+	Max       int      `yaml:"max" json:"max"`
+	Name      string   `yaml:"name" json:"name"`
+	Platforms []string `yaml:"platforms" json:"platforms"`
+}
+
+type SocketRateLimit struct {
+	CloseAfter   int              `yaml:"closeAfter" json:"closeAfter"`
+	Enable       bool             `yaml:"enable" json:"enable"`
+	MaxFrameSize int              `yaml:"maxFrameSize" json:"maxFrameSize"`
+	Rules        []SocketRateRule `yaml:"rules" json:"rules"`
+	WarnAfter    int              `yaml:"warnAfter" json:"warnAfter"`
+}
+
+type SocketRateRule struct {
+	Burst     int     `yaml:"burst" json:"burst"`
+	Event     string  `yaml:"event" json:"event"`
+	Rate      float64 `yaml:"rate" json:"rate"`
+	UserBurst int     `yaml:"userBurst" json:"userBurst"`
+	UserRate  float64 `yaml:"userRate" json:"userRate"`
+}
+
+type SocketWebsocket struct {
+	AllowedOrigins       []string `yaml:"allowedOrigins" json:"allowedOrigins"`
+	Compression          bool     `yaml:"compression" json:"compression"`
+	CompressionLevel     int      `yaml:"compressionLevel" json:"compressionLevel"`
+	CompressionThreshold int      `yaml:"compressionThreshold" json:"compressionThreshold"`
+	ReadBufferSize       int      `yaml:"readBufferSize" json:"readBufferSize"`
+	ReadLimit            int64    `yaml:"readLimit" json:"readLimit"`
+	WriteBufferSize      int      `yaml:"writeBufferSize" json:"writeBufferSize"`
+}
+
+type SocketChannel struct {
+	AckDelay          int `yaml:"ackDelay" json:"ackDelay"`
+	AckMaxDelay       int `yaml:"ackMaxDelay" json:"ackMaxDelay"`
+	AckRetry          int `yaml:"ackRetry" json:"ackRetry"`
+	Buffer            int `yaml:"buffer" json:"buffer"`
+	HeartbeatInterval int `yaml:"heartbeatInterval" json:"heartbeatInterval"`
+	HeartbeatTimeout  int `yaml:"heartbeatTimeout" json:"heartbeatTimeout"`
+	Workers           int `yaml:"workers" json:"workers"`
+}
+
 type Server struct {
+	Tcp       int `yaml:"tcp" json:"tcp"`
 	Websocket int `yaml:"websocket" json:"websocket"`
 }
@@ -1,10 +1,9 @@
 package constant
 
-// IM 渠道分组(用于业务划分，业务间相互隔离)
+// IM 渠道分组(用于业务划分，业务间相互隔离)，其它渠道通过配置注册
 const (
 	// ImChannelChat 默认分组
-	ImChannelChat    = "chat"    // im.Sessions.Chat.Name()
-	ImChannelExample = "example" // im.Sessions.Example.Name()
+	ImChannelChat = "chat" // im.Sessions.Chat.Name()
 )
 
 const (
@@ -12,9 +11,20 @@ const (
 	ImTopicChat        = "im:message:chat:all"
 	ImTopicChatPrivate = "im:message:chat:%s"
 
-	// ImTopicExample Example渠道消息订阅
-	ImTopicExample        = "im:message:example:all"
-	ImTopicExamplePrivate = "im:message:example:%s"
+	// ImTopicChannel 渠道消息订阅，参数为渠道名称
+	ImTopicChannel        = "im:message:%s:all"
+	ImTopicChannelPrivate = "im:message:%s:%s"
+
+	// ImTopicPattern 所有渠道消息订阅
+	ImTopicPattern = "im:message:*"
+)
+
+// 订阅消息传输方式
+const (
+	ImTransportPubSub = "pubsub" // Redis Pub/Sub，节点离线期间的消息会丢失
+	ImTransportStream = "stream" // Redis Streams，每个节点一个消费组，确认后才删除待处理消息
+	ImTransportNats   = "nats"   // NATS
+	ImTransportMemory = "memory" // 进程内通道，仅适用于单节点部署及测试
 )
 
 // 聊天模式
@@ -117,6 +127,9 @@ const (
 	SubEventContactApply      = "sub.im.contact.apply"    // 好友申请消息通知
 	SubEventGroupJoin         = "sub.im.group.join"       // 邀请加入群聊通知
 	SubEventGroupApply        = "sub.im.group.apply"      // 入群申请通知
+	SubEventImClientKick      = "sub.im.client.kick"      // 强制断开用户连接通知
+	SubEventImSessionReplaced = "sub.im.session.replaced" // 同平台新连接替换旧连接通知
+	SubEventChannelPush       = "sub.channel.push"        // 自定义渠道消息推送
 
 	PushEventImMessage         = "im.message"          // 对话消息推送
 	PushEventImMessageKeyboard = "im.message.keyboard" // 键盘输入事件推送
@@ -125,6 +138,36 @@ const (
 	PushEventContactApply      = "im.contact.apply"    // 好友申请消息推送
 	PushEventContactStatus     = "im.contact.status"   // 用户在线状态推送
 	PushEventGroupApply        = "im.group.apply"      // 用户在线状态推送
+
+	PushEventSuccess = "event.success" // 客户端事件处理成功回执
+	PushEventError   = "event.error"   // 客户端事件处理失败回执
+
+	PushEventTokenExpired = "token.expired" // 连接令牌过期通知
+	PushEventSyncResumed  = "sync.resumed"  // 离线消息同步完成通知
+	PushEventRpcReply     = "rpc.reply"     // 携带 req_id 的客户端事件处理回执
+	PushEventImKicked     = "im.kicked"     // 连接被强制断开通知
+
+	PushEventImSessionReplaced = "im.session.replaced" // 连接被同平台新登录替换通知
+)
+
+// 强制断开连接原因
+const (
+	KickReasonAdmin         = "admin"          // 管理员操作
+	KickReasonLogout        = "logout"         // 退出全部设备
+	KickReasonPasswordReset = "password_reset" // 重置密码
+	KickReasonBanned        = "banned"         // 账号封禁
+)
+
+// 客户端上行事件
+const (
+	EventImMessagePublish  = "im.message.publish"  // 发送聊天消息
+	EventImMessageKeyboard = "im.message.keyboard" // 键盘输入事件
+	EventImMessageRead     = "im.message.read"     // 消息已读事件
+	EventImMessageRevoke   = "im.message.revoke"   // 撤回聊天消息
+	EventTokenRefresh      = "token.refresh"       // 续期连接令牌
+	EventSyncResume        = "sync.resume"         // 断线重连同步离线消息
+	EventTalkSessionList   = "talk.session.list"   // 获取会话列表
+	EventTalkRecords       = "talk.records"        // 分页获取聊天记录
 )
 
 const (
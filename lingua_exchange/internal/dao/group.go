@@ -102,7 +102,7 @@ func (d *groupDao) GroupList(ctx context.Context, id int) ([]*types.GroupItem, e
 }
 
 func (d *groupDao) FindAll(ctx context.Context, arg ...func(*gorm.DB)) ([]*model.Group, error) {
-	db := d.db.Model(ctx)
+	db := d.db.WithContext(ctx).Model(&model.Group{})
 	for _, fn := range arg {
 		fn(db)
 	}
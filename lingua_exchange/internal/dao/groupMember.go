@@ -56,7 +56,7 @@ func NewGroupMemberDao(db *gorm.DB, xCache cache.GroupMemberCache) GroupMemberDa
 }
 
 func (g groupMemberDao) FindAll(ctx context.Context, arg ...func(*gorm.DB)) ([]*model.GroupMember, error) {
-	tx := g.db.Model(ctx)
+	tx := g.db.WithContext(ctx).Model(&model.GroupMember{})
 	for _, fn := range arg {
 		fn(tx)
 	}
@@ -71,7 +71,7 @@ func (g groupMemberDao) FindAll(ctx context.Context, arg ...func(*gorm.DB)) ([]*
 
 func (g groupMemberDao) FindByWhere(ctx context.Context, where string, args ...any) (*model.GroupMember, error) {
 	var item *model.GroupMember
-	err := g.db.Model(ctx).Where(where, args...).First(&item).Error
+	err := g.db.WithContext(ctx).Model(&model.GroupMember{}).Where(where, args...).First(&item).Error
 	if err != nil {
 		return nil, err
 	}
@@ -79,7 +79,7 @@ func (g groupMemberDao) FindByWhere(ctx context.Context, where string, args ...a
 }
 
 func (g groupMemberDao) UpdateWhere(ctx context.Context, data any, where string, args ...any) (int64, error) {
-	updates := g.db.Model(ctx).Where(where, args...).Updates(data)
+	updates := g.db.WithContext(ctx).Model(&model.GroupMember{}).Where(where, args...).Updates(data)
 	return updates.RowsAffected, updates.Error
 }
 
@@ -112,20 +112,20 @@ func (g groupMemberDao) IsMember(ctx context.Context, gid, uid int, cache bool)
 
 func (g groupMemberDao) FindByUserId(ctx context.Context, gid, uid int) (*model.GroupMember, error) {
 	member := &model.GroupMember{}
-	err := g.db.Model(ctx).Where("group_id = ? and user_id = ?", gid, uid).First(member).Error
+	err := g.db.WithContext(ctx).Model(&model.GroupMember{}).Where("group_id = ? and user_id = ?", gid, uid).First(member).Error
 	return member, err
 }
 
 func (g groupMemberDao) GetMemberIds(ctx context.Context, groupId int) []int {
 	var ids []int
-	_ = g.db.Model(ctx).Select("user_id").Where("group_id = ? and is_quit = ?", groupId, GroupMemberQuitStatusNo).Scan(&ids)
+	_ = g.db.WithContext(ctx).Model(&model.GroupMember{}).Select("user_id").Where("group_id = ? and is_quit = ?", groupId, GroupMemberQuitStatusNo).Scan(&ids)
 
 	return ids
 }
 
 func (g groupMemberDao) GetUserGroupIds(ctx context.Context, uid int) []int {
 	var ids []int
-	_ = g.db.Model(ctx).Where("user_id = ? and is_quit = ?", uid, GroupMemberQuitStatusNo).Pluck("group_id", &ids)
+	_ = g.db.WithContext(ctx).Model(&model.GroupMember{}).Where("user_id = ? and is_quit = ?", uid, GroupMemberQuitStatusNo).Pluck("group_id", &ids)
 
 	return ids
 }
@@ -137,7 +137,7 @@ func (g groupMemberDao) CountMemberTotal(ctx context.Context, gid int) int64 {
 
 func (g groupMemberDao) GetMemberRemark(ctx context.Context, groupId int, userId int) string {
 	var remarks string
-	g.db.Model(ctx).Select("user_card").Where("group_id = ? and user_id = ?", groupId, userId).Scan(&remarks)
+	g.db.WithContext(ctx).Model(&model.GroupMember{}).Select("user_card").Where("group_id = ? and user_id = ?", groupId, userId).Scan(&remarks)
 
 	return remarks
 }
@@ -205,17 +205,17 @@ func (g groupMemberDao) Handover(ctx context.Context, groupId int, userId int, m
 }
 
 func (g groupMemberDao) SetLeaderStatus(ctx context.Context, groupId int, userId int, leader int) error {
-	return g.db.Model(ctx).Where("group_id = ? and user_id = ?", groupId, userId).UpdateColumn("leader", leader).Error
+	return g.db.WithContext(ctx).Model(&model.GroupMember{}).Where("group_id = ? and user_id = ?", groupId, userId).UpdateColumn("leader", leader).Error
 }
 
 func (g groupMemberDao) SetMuteStatus(ctx context.Context, groupId int, userId int, status int) error {
-	return g.db.Model(ctx).Where("group_id = ? and user_id = ?", groupId, userId).UpdateColumn("is_mute", status).Error
+	return g.db.WithContext(ctx).Model(&model.GroupMember{}).Where("group_id = ? and user_id = ?", groupId, userId).UpdateColumn("is_mute", status).Error
 }
 
 func (g groupMemberDao) queryExist(ctx context.Context, where string, args ...any) (bool, error) {
 
 	var count int64
-	err := g.db.Model(ctx).Select("1").Where(where, args...).Limit(1).Scan(&count).Error
+	err := g.db.WithContext(ctx).Model(&model.GroupMember{}).Select("1").Where(where, args...).Limit(1).Scan(&count).Error
 	if err != nil {
 		return false, err
 	}
@@ -227,7 +227,7 @@ func (g groupMemberDao) queryExist(ctx context.Context, where string, args ...an
 func (g groupMemberDao) queryCount(ctx context.Context, where string, args ...any) (int64, error) {
 
 	var count int64
-	err := g.db.Model(ctx).Where(where, args...).Count(&count).Error
+	err := g.db.WithContext(ctx).Model(&model.GroupMember{}).Where(where, args...).Count(&count).Error
 	if err != nil {
 		return 0, err
 	}
@@ -0,0 +1,38 @@
+package dao
+
+import (
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/zhufuyi/sponge/pkg/gotest"
+)
+
+func Test_groupMemberDao_GetUserGroupIds(t *testing.T) {
+	d := gotest.NewDao(nil, nil)
+	defer d.Close()
+
+	d.SQLMock.ExpectQuery("SELECT `group_id` FROM `group_member` WHERE .*user_id = \\? and is_quit = \\?").
+		WithArgs(7, GroupMemberQuitStatusNo).
+		WillReturnRows(sqlmock.NewRows([]string{"group_id"}).AddRow(1).AddRow(2))
+
+	ids := NewGroupMemberDao(d.DB, nil).GetUserGroupIds(d.Ctx, 7)
+	assert.Equal(t, []int{1, 2}, ids)
+	assert.NoError(t, d.SQLMock.ExpectationsWereMet())
+}
+
+func Test_groupMemberDao_UpdateWhere(t *testing.T) {
+	d := gotest.NewDao(nil, nil)
+	defer d.Close()
+
+	d.SQLMock.ExpectBegin()
+	d.SQLMock.ExpectExec("UPDATE `group_member` SET `is_mute`=\\?,`updated_at`=\\? WHERE group_id = \\?").
+		WithArgs(1, sqlmock.AnyArg(), 3).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	d.SQLMock.ExpectCommit()
+
+	n, err := NewGroupMemberDao(d.DB, nil).UpdateWhere(d.Ctx, map[string]any{"is_mute": 1}, "group_id = ?", 3)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), n)
+	assert.NoError(t, d.SQLMock.ExpectationsWereMet())
+}
@@ -0,0 +1,26 @@
+package dao
+
+import (
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/zhufuyi/sponge/pkg/gotest"
+	"gorm.io/gorm"
+)
+
+func Test_groupDao_FindAll(t *testing.T) {
+	d := gotest.NewDao(nil, nil)
+	defer d.Close()
+
+	d.SQLMock.ExpectQuery("SELECT \\* FROM `group` WHERE id in \\(\\?,\\?\\)").
+		WithArgs(1, 2).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "a").AddRow(2, "b"))
+
+	items, err := NewGroupDao(d.DB, nil).FindAll(d.Ctx, func(db *gorm.DB) {
+		db.Where("id in ?", []int{1, 2})
+	})
+	assert.NoError(t, err)
+	assert.Len(t, items, 2)
+	assert.NoError(t, d.SQLMock.ExpectationsWereMet())
+}
@@ -0,0 +1,70 @@
+package dao
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+	"lingua_exchange/internal/model"
+)
+
+var _ TalkMessageOutboxDao = (*talkMessageOutboxDao)(nil)
+
+// TalkMessageOutboxDao 消息推送发件箱
+type TalkMessageOutboxDao interface {
+	CreateByTx(ctx context.Context, tx *gorm.DB, table *model.TalkMessageOutbox) (uint64, error)
+	FindPending(ctx context.Context, before time.Time, limit int) ([]*model.TalkMessageOutbox, error)
+	MarkPublished(ctx context.Context, id uint64) error
+	IncrRetry(ctx context.Context, id uint64, maxRetry uint) error
+	Purge(ctx context.Context, before time.Time, limit int) (int64, error)
+}
+
+type talkMessageOutboxDao struct {
+	db *gorm.DB
+}
+
+func NewTalkMessageOutboxDao(db *gorm.DB) TalkMessageOutboxDao {
+	return &talkMessageOutboxDao{db: db}
+}
+
+// CreateByTx 与业务数据在同一事务中写入发件箱
+func (d *talkMessageOutboxDao) CreateByTx(ctx context.Context, tx *gorm.DB, table *model.TalkMessageOutbox) (uint64, error) {
+	err := tx.WithContext(ctx).Create(table).Error
+	return table.ID, err
+}
+
+// FindPending 查询指定时间之前写入且仍未投递的消息
+func (d *talkMessageOutboxDao) FindPending(ctx context.Context, before time.Time, limit int) ([]*model.TalkMessageOutbox, error) {
+	var items []*model.TalkMessageOutbox
+	err := d.db.WithContext(ctx).
+		Where("status = ? and created_at < ?", model.OutboxStatusPending, before).
+		Order("id asc").
+		Limit(limit).
+		Find(&items).Error
+	return items, err
+}
+
+// MarkPublished 标记已投递
+func (d *talkMessageOutboxDao) MarkPublished(ctx context.Context, id uint64) error {
+	return d.db.WithContext(ctx).Model(&model.TalkMessageOutbox{}).Where("id = ?", id).
+		Updates(map[string]any{"status": model.OutboxStatusPublished, "updated_at": time.Now()}).Error
+}
+
+// IncrRetry 增加重试次数，超过最大重试次数后标记为投递失败
+func (d *talkMessageOutboxDao) IncrRetry(ctx context.Context, id uint64, maxRetry uint) error {
+	return d.db.WithContext(ctx).Model(&model.TalkMessageOutbox{}).Where("id = ?", id).
+		Updates(map[string]any{
+			"retry":      gorm.Expr("retry + 1"),
+			"status":     gorm.Expr("IF(retry >= ?, ?, status)", maxRetry, model.OutboxStatusFailed),
+			"updated_at": time.Now(),
+		}).Error
+}
+
+// Purge 物理删除指定时间之前写入且已投递或投递失败的消息
+func (d *talkMessageOutboxDao) Purge(ctx context.Context, before time.Time, limit int) (int64, error) {
+	res := d.db.WithContext(ctx).Unscoped().
+		Where("status in ? and created_at < ?", []int{model.OutboxStatusPublished, model.OutboxStatusFailed}, before).
+		Limit(limit).
+		Delete(&model.TalkMessageOutbox{})
+	return res.RowsAffected, res.Error
+}
@@ -36,6 +36,7 @@ type TalkRecordsDao interface {
 	GetByIDs(ctx context.Context, ids []string) (map[string]*model.TalkRecords, error)
 	GetByLastID(ctx context.Context, lastID string, limit int, sort string) ([]*model.TalkRecords, error)
 
+	CreateByTx(ctx context.Context, tx *gorm.DB, table *model.TalkRecords) (uint64, error)
 	DeleteByTx(ctx context.Context, tx *gorm.DB, id string) error
 	UpdateByTx(ctx context.Context, tx *gorm.DB, table *model.TalkRecords) error
 	FindTalkRecord(ctx context.Context, id string) (*types.TalkRecordItem, error)
@@ -79,6 +80,8 @@ func (d *talkRecordsDao) FindAllTalkRecords(ctx context.Context, opt *types.Find
 			MsgType:    opt.MsgType,
 			Cursor:     cursor,
 			Limit:      opt.Limit + 10, // 多查几条数据
+			Forward:    opt.Forward,
+			Since:      opt.Since,
 		})
 
 		if err != nil {
@@ -142,10 +145,16 @@ func (d *talkRecordsDao) findAllRecords(ctx context.Context, opt *types.FindAllT
 		"talk_records.created_at",
 	})
 
-	if opt.Cursor > 0 {
+	if opt.Forward {
+		query.Where("talk_records.sequence > ?", opt.Cursor)
+	} else if opt.Cursor > 0 {
 		query.Where("talk_records.sequence < ?", opt.Cursor)
 	}
 
+	if opt.Since > 0 {
+		query.Where("talk_records.created_at > ?", time.Unix(opt.Since, 0))
+	}
+
 	if opt.TalkType == constant.ChatPrivateMode {
 		subQuery := d.db.Where("talk_records.user_id = ? and talk_records.receiver_id = ?", opt.UserId, opt.ReceiverId)
 		subQuery.Or("talk_records.user_id = ? and talk_records.receiver_id = ?", opt.ReceiverId, opt.UserId)
@@ -160,7 +169,11 @@ func (d *talkRecordsDao) findAllRecords(ctx context.Context, opt *types.FindAllT
 	}
 
 	query.Where("talk_records.talk_type = ?", opt.TalkType)
-	query.Order("talk_records.sequence desc").Limit(opt.Limit)
+	if opt.Forward {
+		query.Order("talk_records.sequence asc").Limit(opt.Limit)
+	} else {
+		query.Order("talk_records.sequence desc").Limit(opt.Limit)
+	}
 
 	var items []*types.QueryTalkRecord
 	if err := query.Scan(&items).Error; err != nil {
@@ -24,6 +24,7 @@ type TalkSessionDao interface {
 	Delete(ctx context.Context, uid int, id int) error
 	Top(ctx context.Context, opt *model.TalkSessionTopOpt) error
 	Disturb(ctx context.Context, opt *model.TalkSessionDisturbOpt) error
+	Touch(ctx context.Context, talkType int, receiverId int) error
 }
 
 type talkSessionDao struct {
@@ -46,6 +47,13 @@ func (t talkSessionDao) Top(ctx context.Context, opt *model.TalkSessionTopOpt) e
 	return err
 }
 
+// Touch 更新接收者相关会话的最后活跃时间
+func (t talkSessionDao) Touch(ctx context.Context, talkType int, receiverId int) error {
+	return t.db.WithContext(ctx).Model(&model.TalkSession{}).
+		Where("talk_type = ? and receiver_id = ? and is_delete = 0", talkType, receiverId).
+		Update("updated_at", time.Now()).Error
+}
+
 func (t talkSessionDao) Delete(ctx context.Context, uid int, id int) error {
 	_, err := t.UpdateWhere(ctx, map[string]any{"is_delete": 1, "updated_at": time.Now()}, "id = ? and user_id = ?", id, uid)
 	return err
@@ -159,6 +167,6 @@ func (t talkSessionDao) FindByWhere(ctx context.Context, where string, args ...a
 
 // UpdateWhere 批量更新
 func (t talkSessionDao) UpdateWhere(ctx context.Context, data any, where string, args ...any) (int64, error) {
-	res := t.db.Model(ctx).Where(where, args...).Updates(data)
+	res := t.db.WithContext(ctx).Model(&model.TalkSession{}).Where(where, args...).Updates(data)
 	return res.RowsAffected, res.Error
 }
@@ -0,0 +1,24 @@
+package dao
+
+import (
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/zhufuyi/sponge/pkg/gotest"
+	"lingua_exchange/internal/model"
+)
+
+func Test_talkSessionDao_Disturb(t *testing.T) {
+	d := gotest.NewDao(nil, nil)
+	defer d.Close()
+
+	d.SQLMock.ExpectBegin()
+	d.SQLMock.ExpectExec("UPDATE `talk_session` SET `is_disturb`=\\?,`updated_at`=\\? WHERE \\(user_id = \\? and receiver_id = \\? and talk_type = \\?\\)").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	d.SQLMock.ExpectCommit()
+
+	err := NewTalkSessionDao(d.DB).Disturb(d.Ctx, &model.TalkSessionDisturbOpt{UserId: 1, ReceiverId: 2, TalkType: 1, IsDisturb: 1})
+	assert.NoError(t, err)
+	assert.NoError(t, d.SQLMock.ExpectationsWereMet())
+}
@@ -13,4 +13,17 @@ var (
 	ErrPublishMessageError      = errcode.NewError(chatBaseCode+5, "publish message  error"+chatName)
 	ErrPublishTextMessageError  = errcode.NewError(chatBaseCode+6, "publish message text error"+chatName)
 	ErrPublishCodeMessageError  = errcode.NewError(chatBaseCode+7, "publish code message error"+chatName)
+	ErrEventInvalid             = errcode.NewError(chatBaseCode+8, "invalid socket event "+chatName)
+	ErrEventNotSupported        = errcode.NewError(chatBaseCode+9, "socket event not supported "+chatName)
+	ErrEventParams              = errcode.NewError(chatBaseCode+10, "invalid socket event params "+chatName)
+	ErrMessageTypeNotSupported  = errcode.NewError(chatBaseCode+11, "message type not supported "+chatName)
+	ErrRevokeMessageError       = errcode.NewError(chatBaseCode+12, "revoke message error "+chatName)
+	ErrReadMessageError         = errcode.NewError(chatBaseCode+13, "read message error "+chatName)
+	ErrKeyboardMessageError     = errcode.NewError(chatBaseCode+14, "keyboard message error "+chatName)
+	ErrSocketUnauthorized       = errcode.NewError(chatBaseCode+15, "socket connection unauthorized "+chatName)
+	ErrSocketChannelNotFound    = errcode.NewError(chatBaseCode+16, "socket channel not found "+chatName)
+	ErrSocketTokenRefresh       = errcode.NewError(chatBaseCode+17, "socket token refresh error "+chatName)
+	ErrSyncResume               = errcode.NewError(chatBaseCode+18, "sync resume error "+chatName)
+	ErrSocketCodecNotSupported  = errcode.NewError(chatBaseCode+19, "socket codec not supported "+chatName)
+	ErrEventTimeout             = errcode.NewError(chatBaseCode+20, "socket event handle timeout "+chatName)
 )
@@ -0,0 +1,32 @@
+package handler
+
+import (
+	"sync"
+	"time"
+
+	"lingua_exchange/pkg/socket"
+)
+
+// ChannelEvent 渠道客户端事件处理
+type ChannelEvent interface {
+	OnOpen(client socket.IClient)
+	OnMessage(client socket.IClient, message []byte)
+	OnClose(client socket.IClient, code int, text string)
+	WatchToken(client socket.IClient, expireAt time.Time)
+}
+
+var channelEvents sync.Map
+
+// RegisterChannelEvent 注册渠道的客户端事件处理，未注册的渠道使用默认的聊天事件
+func RegisterChannelEvent(channel string, event ChannelEvent) {
+	channelEvents.Store(channel, event)
+}
+
+// channelEvent 获取渠道的客户端事件处理
+func (m messageHandler) channelEvent(channel string) ChannelEvent {
+	if val, ok := channelEvents.Load(channel); ok {
+		return val.(ChannelEvent)
+	}
+
+	return m.event
+}
@@ -0,0 +1,115 @@
+package handler
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/zhufuyi/sponge/pkg/gin/response"
+	"lingua_exchange/internal/bus"
+	"lingua_exchange/internal/cache"
+	"lingua_exchange/internal/constant"
+	"lingua_exchange/internal/ecode"
+	"lingua_exchange/internal/types"
+	"lingua_exchange/pkg/jwt"
+	"lingua_exchange/pkg/socket"
+)
+
+// clusterNode 集群节点运行状态
+type clusterNode struct {
+	Sid        string                `json:"sid"`
+	Uptime     int64                 `json:"uptime"`      // 运行时长(秒)
+	ReportedAt int64                 `json:"reported_at"` // 最后上报时间，0 表示尚未上报
+	Channels   []*cache.ChannelStats `json:"channels"`
+}
+
+// ClusterNodes 获取集群运行中的节点及各渠道连接状态
+func (m messageHandler) ClusterNodes(ctx *gin.Context) {
+	servers := m.serverCache.All(ctx, 1)
+	sort.Strings(servers)
+
+	stats, err := m.serverCache.GetStats(ctx, servers)
+	if err != nil {
+		response.Error(ctx, ecode.InternalServerError, err)
+		return
+	}
+
+	now := time.Now().Unix()
+	nodes := make([]*clusterNode, 0, len(servers))
+	for _, sid := range servers {
+		node := &clusterNode{Sid: sid, Channels: make([]*cache.ChannelStats, 0)}
+		if item, ok := stats[sid]; ok {
+			node.Uptime = now - item.StartedAt
+			node.ReportedAt = item.ReportedAt
+			node.Channels = item.Channels
+		}
+
+		nodes = append(nodes, node)
+	}
+
+	response.Success(ctx, gin.H{"nodes": nodes})
+}
+
+// ClusterKick 通知集群各节点强制断开用户连接
+func (m messageHandler) ClusterKick(ctx *gin.Context) {
+	form := &types.ClientKickRequest{}
+	if err := ctx.ShouldBindJSON(form); err != nil {
+		response.Error(ctx, ecode.InvalidParams)
+		return
+	}
+
+	if form.Reason == "" {
+		form.Reason = constant.KickReasonAdmin
+	}
+
+	err := kickClient(ctx, m.event.Publisher, &types.ConsumeClientKick{
+		UserID:   form.Uid,
+		Platform: form.Platform,
+		Device:   form.Device,
+		Reason:   form.Reason,
+	})
+	if err != nil {
+		response.Error(ctx, ecode.InternalServerError, err)
+		return
+	}
+
+	response.Success(ctx)
+}
+
+// kickClient 发布强制断开用户连接通知
+func kickClient(ctx context.Context, publisher bus.Publisher, kick *types.ConsumeClientKick) error {
+	return publisher.Publish(ctx, constant.ImTopicChat, bus.NewMessage(constant.SubEventImClientKick, kick))
+}
+
+// signOutClient 注销用户全部令牌并强制断开其连接
+func signOutClient(ctx context.Context, publisher bus.Publisher, uid int, reason string) error {
+	if err := jwt.RevokeUserTokens(ctx, uid); err != nil {
+		return err
+	}
+
+	return kickClient(ctx, publisher, &types.ConsumeClientKick{UserID: uid, Reason: reason})
+}
+
+// ClusterSessions 获取用户在集群各节点的连接
+func (m messageHandler) ClusterSessions(ctx *gin.Context) {
+	uid, err := strconv.Atoi(ctx.Query("uid"))
+	if err != nil {
+		response.Error(ctx, ecode.InvalidParams)
+		return
+	}
+
+	sessions := make([]*cache.UserSession, 0)
+	for _, ch := range socket.Session.Channels() {
+		items, err := m.messageCache.UserSessions(ctx, ch.Name(), uid)
+		if err != nil {
+			response.Error(ctx, ecode.InternalServerError, err)
+			return
+		}
+
+		sessions = append(sessions, items...)
+	}
+
+	response.Success(ctx, gin.H{"uid": uid, "sessions": sessions})
+}
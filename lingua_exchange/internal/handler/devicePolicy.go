@@ -0,0 +1,110 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/zhufuyi/sponge/pkg/logger"
+	"lingua_exchange/internal/bus"
+	"lingua_exchange/internal/cache"
+	"lingua_exchange/internal/config"
+	"lingua_exchange/internal/constant"
+	"lingua_exchange/internal/types"
+	"lingua_exchange/pkg/socket"
+)
+
+// devicePolicy 多设备登录策略，同一平台分组内的连接数超过上限时由新连接替换最早的连接
+type devicePolicy struct {
+	sid          string
+	groups       map[string]config.SocketDeviceGroup // 平台(小写) -> 分组
+	messageCache *cache.MessageCache
+	publisher    bus.Publisher
+}
+
+// newDevicePolicy 未启用时返回空，不限制连接数
+func newDevicePolicy(conf config.SocketDevicePolicy, sid string, messageCache *cache.MessageCache, publisher bus.Publisher) *devicePolicy {
+	if !conf.Enable {
+		return nil
+	}
+
+	p := &devicePolicy{sid: sid, groups: make(map[string]config.SocketDeviceGroup), messageCache: messageCache, publisher: publisher}
+	for _, group := range conf.Groups {
+		if group.Max <= 0 {
+			continue
+		}
+
+		for _, platform := range group.Platforms {
+			p.groups[strings.ToLower(platform)] = group
+		}
+	}
+
+	return p
+}
+
+// takeover 新连接建立后通知所在节点断开同平台分组内超出上限的旧连接
+func (p *devicePolicy) takeover(client socket.IClient) {
+	if p == nil {
+		return
+	}
+
+	meta := client.Meta()
+	group, ok := p.groups[strings.ToLower(meta.Platform)]
+	if !ok {
+		return
+	}
+
+	ctx := context.Background()
+	sessions, err := p.messageCache.UserSessions(ctx, client.Channel().Name(), client.Uid())
+	if err != nil {
+		logger.Warn("device policy load sessions error", logger.Int("uid", client.Uid()), logger.Err(err))
+		return
+	}
+
+	replaced := make(map[string][]int64)
+	for _, session := range p.replaced(group, sessions) {
+		if session.Sid == p.sid && session.Cid == client.Cid() {
+			continue
+		}
+
+		replaced[session.Sid] = append(replaced[session.Sid], session.Cid)
+	}
+
+	for sid, cids := range replaced {
+		err := p.publisher.Publish(ctx, fmt.Sprintf(constant.ImTopicChatPrivate, sid), bus.NewMessage(constant.SubEventImSessionReplaced, &types.ConsumeSessionReplaced{
+			UserID:   client.Uid(),
+			Channel:  client.Channel().Name(),
+			Cids:     cids,
+			Platform: meta.Platform,
+			Device:   meta.Device,
+		}))
+		if err != nil {
+			logger.Warn("device policy publish error", logger.Int("uid", client.Uid()), logger.String("sid", sid), logger.Err(err))
+		}
+	}
+}
+
+// replaced 分组内按连接时间保留最新的 Max 个连接，返回其余需替换的连接
+// 同时建立的连接在各自节点上得到相同的结果，只保留最新的连接
+func (p *devicePolicy) replaced(group config.SocketDeviceGroup, sessions []*cache.UserSession) []*cache.UserSession {
+	items := make([]*cache.UserSession, 0, len(sessions))
+	for _, session := range sessions {
+		if g, ok := p.groups[strings.ToLower(session.Platform)]; ok && g.Name == group.Name {
+			items = append(items, session)
+		}
+	}
+
+	if len(items) <= group.Max {
+		return nil
+	}
+
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].ConnectedAt != items[j].ConnectedAt {
+			return items[i].ConnectedAt > items[j].ConnectedAt
+		}
+		return items[i].Cid > items[j].Cid
+	})
+
+	return items[group.Max:]
+}
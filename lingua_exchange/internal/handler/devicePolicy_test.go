@@ -0,0 +1,50 @@
+package handler
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"lingua_exchange/internal/cache"
+	"lingua_exchange/internal/config"
+	"lingua_exchange/pkg/socket"
+)
+
+func Test_devicePolicy_replaced(t *testing.T) {
+	assert.Nil(t, newDevicePolicy(config.SocketDevicePolicy{}, "node-1", nil, nil))
+
+	p := newDevicePolicy(config.SocketDevicePolicy{
+		Enable: true,
+		Groups: []config.SocketDeviceGroup{
+			{Name: "mobile", Platforms: []string{"iOS", "android"}, Max: 1},
+			{Name: "desktop", Platforms: []string{"windows", "macos"}, Max: 2},
+		},
+	}, "node-1", nil, nil)
+
+	session := func(sid string, cid int64, platform string, connectedAt int64) *cache.UserSession {
+		return &cache.UserSession{Sid: sid, Channel: "chat", Cid: cid, ClientMeta: &socket.ClientMeta{Platform: platform, ConnectedAt: connectedAt}}
+	}
+
+	sessions := []*cache.UserSession{
+		session("node-1", 1, "ios", 100),
+		session("node-2", 2, "android", 200),
+		session("node-2", 3, "android", 200),
+		session("node-1", 4, "web", 50),
+		session("node-1", 5, "windows", 100),
+		session("node-2", 6, "macos", 300),
+		session("node-1", 7, "", 10),
+	}
+
+	// 平台不区分大小写，同一时间建立的连接保留ID较大者
+	replaced := p.replaced(p.groups["ios"], sessions)
+	assert.Equal(t, []*cache.UserSession{sessions[1], sessions[0]}, replaced)
+
+	// 未超出上限
+	assert.Empty(t, p.replaced(p.groups["windows"], sessions))
+
+	// 同一秒内先后建立的连接按毫秒区分，不受各节点客户端ID大小影响
+	sessions = []*cache.UserSession{
+		session("node-1", 9, "ios", 1700000000100),
+		session("node-2", 8, "android", 1700000000900),
+	}
+	assert.Equal(t, []*cache.UserSession{sessions[0]}, p.replaced(p.groups["ios"], sessions))
+}
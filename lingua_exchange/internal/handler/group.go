@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"time"
 
+	"lingua_exchange/internal/bus"
 	"lingua_exchange/internal/cache"
 	"lingua_exchange/internal/constant"
 	"lingua_exchange/internal/dao"
@@ -49,6 +50,7 @@ type groupHandler struct {
 	talkRecordCache  cache.TalkRecordsCache
 	talkRecordsDao   dao.TalkRecordsDao
 	redis            *redis.Client
+	publisher        bus.Publisher
 	groupMemberDao   dao.GroupMemberDao
 	groupMemberCache cache.GroupMemberCache
 	redisLock        *cache.RedisLock
@@ -164,15 +166,12 @@ func (g groupHandler) Create(ctx *gin.Context) {
 	}
 
 	// 广播网关将在线的用户加入房间
-	body := map[string]any{
-		"event": constant.SubEventGroupJoin,
-		"data": jsonutil.Encode(map[string]any{
-			"group_id": group.ID,
-			"uids":     uids,
-		}),
-	}
+	body := bus.NewMessage(constant.SubEventGroupJoin, map[string]any{
+		"group_id": group.ID,
+		"uids":     uids,
+	})
 
-	g.redis.Publish(ctx, constant.ImTopicChat, jsonutil.Encode(body))
+	_ = g.publisher.Publish(ctx, constant.ImTopicChat, body)
 
 	response.Success(ctx, gin.H{
 		"group_id": &types.GroupCreateReply{
@@ -400,23 +399,17 @@ func (g groupHandler) invite(prams *types.GroupInviteRequest, ids []int, uid int
 	}
 
 	// 广播网关将在线的用户加入房间
-	g.redis.Publish(ctx, constant.ImTopicChat, jsonutil.Encode(map[string]any{
-		"event": constant.SubEventGroupJoin,
-		"data": jsonutil.Encode(map[string]any{
-			"type":     1,
-			"group_id": prams.GroupID,
-			"uids":     ids,
-		}),
+	_ = g.publisher.Publish(ctx, constant.ImTopicChat, bus.NewMessage(constant.SubEventGroupJoin, map[string]any{
+		"type":     1,
+		"group_id": prams.GroupID,
+		"uids":     ids,
 	}))
 
-	g.redis.Publish(ctx, constant.ImTopicChat, jsonutil.Encode(map[string]any{
-		"event": constant.SubEventImMessage,
-		"data": jsonutil.Encode(map[string]any{
-			"sender_id":   record.UserID,
-			"receiver_id": record.ReceiverID,
-			"talk_type":   record.TalkType,
-			"msg_id":      record.MsgID,
-		}),
+	_ = g.publisher.Publish(ctx, constant.ImTopicChat, bus.NewMessage(constant.SubEventImMessage, map[string]any{
+		"sender_id":   record.UserID,
+		"receiver_id": record.ReceiverID,
+		"talk_type":   record.TalkType,
+		"msg_id":      record.MsgID,
 	}))
 
 	return nil
@@ -849,6 +842,7 @@ func NewGroupHandler() GroupHandler {
 		talkRecordsDao:   dao.NewTalkRecordsDao(model.GetDB(), cache.NewTalkRecordsCache(model.GetCacheType())),
 		talkRecordCache:  cache.NewTalkRecordsCache(model.GetCacheType()),
 		redis:            model.GetRedisCli(),
+		publisher:        bus.Get(),
 		groupMemberDao:   dao.NewGroupMemberDao(model.GetDB(), cache.NewGroupMemberCache(model.GetCacheType())),
 		talkSessionDao:   dao.NewTalkSessionDao(model.GetDB()),
 		groupMemberCache: cache.NewGroupMemberCache(model.GetCacheType()),
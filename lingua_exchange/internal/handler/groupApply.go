@@ -12,6 +12,7 @@ import (
 	"github.com/zhufuyi/sponge/pkg/gin/response"
 	"github.com/zhufuyi/sponge/pkg/logger"
 	"gorm.io/gorm"
+	"lingua_exchange/internal/bus"
 	"lingua_exchange/internal/cache"
 	"lingua_exchange/internal/constant"
 	"lingua_exchange/internal/dao"
@@ -42,6 +43,7 @@ type groupApplyHandler struct {
 	groupDao         dao.GroupDao
 	groupMemberDao   dao.GroupMemberDao
 	redis            *redis.Client
+	publisher        bus.Publisher
 	db               *gorm.DB
 	talkRecordsCache cache.TalkRecordsCache
 }
@@ -52,6 +54,7 @@ func NewGroupApplyHandler() GroupApplyHandler {
 		groupDao:         dao.NewGroupDao(model.GetDB(), cache.NewGroupCache(model.GetCacheType())),
 		groupMemberDao:   dao.NewGroupMemberDao(model.GetDB(), cache.NewGroupMemberCache(model.GetCacheType())),
 		redis:            model.GetRedisCli(),
+		publisher:        bus.Get(),
 		iCache:           cache.NewGroupApplyCache(model.GetCacheType()),
 		db:               model.GetDB(),
 		talkRecordsCache: cache.NewTalkRecordsCache(model.GetCacheType()),
@@ -104,12 +107,9 @@ func (g groupApplyHandler) Create(c *gin.Context) {
 		g.iCache.Incr(ctx, uint64(groupMember.UserID))
 	}
 
-	g.redis.Publish(ctx, constant.ImTopicChat, jsonutil.Encode(map[string]any{
-		"event": constant.SubEventGroupApply,
-		"data": jsonutil.Encode(map[string]any{
-			"group_id": params.GroupID,
-			"user_id":  uid,
-		}),
+	_ = g.publisher.Publish(ctx, constant.ImTopicChat, bus.NewMessage(constant.SubEventGroupApply, map[string]any{
+		"group_id": params.GroupID,
+		"user_id":  uid,
 	}))
 
 	response.Success(c, "ok")
@@ -317,23 +317,17 @@ func (g groupApplyHandler) invite(gid int, ids []int, uid int, ctx context.Conte
 	}
 
 	// 广播网关将在线的用户加入房间
-	g.redis.Publish(ctx, constant.ImTopicChat, jsonutil.Encode(map[string]any{
-		"event": constant.SubEventGroupJoin,
-		"data": jsonutil.Encode(map[string]any{
-			"type":     1,
-			"group_id": gid,
-			"uids":     ids,
-		}),
+	_ = g.publisher.Publish(ctx, constant.ImTopicChat, bus.NewMessage(constant.SubEventGroupJoin, map[string]any{
+		"type":     1,
+		"group_id": gid,
+		"uids":     ids,
 	}))
 
-	g.redis.Publish(ctx, constant.ImTopicChat, jsonutil.Encode(map[string]any{
-		"event": constant.SubEventImMessage,
-		"data": jsonutil.Encode(map[string]any{
-			"sender_id":   record.UserID,
-			"receiver_id": record.ReceiverID,
-			"talk_type":   record.TalkType,
-			"msg_id":      record.MsgID,
-		}),
+	_ = g.publisher.Publish(ctx, constant.ImTopicChat, bus.NewMessage(constant.SubEventImMessage, map[string]any{
+		"sender_id":   record.UserID,
+		"receiver_id": record.ReceiverID,
+		"talk_type":   record.TalkType,
+		"msg_id":      record.MsgID,
 	}))
 
 	return nil
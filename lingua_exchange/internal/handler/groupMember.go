@@ -11,6 +11,7 @@ import (
 	"github.com/zhufuyi/sponge/pkg/gin/response"
 	"github.com/zhufuyi/sponge/pkg/logger"
 	"gorm.io/gorm"
+	"lingua_exchange/internal/bus"
 	"lingua_exchange/internal/cache"
 	"lingua_exchange/internal/constant"
 	"lingua_exchange/internal/dao"
@@ -38,6 +39,7 @@ type groupMemberHandler struct {
 	groupDao         dao.GroupDao
 	db               *gorm.DB
 	redis            *redis.Client
+	publisher        bus.Publisher
 	talkRecordCache  cache.TalkRecordsCache
 }
 
@@ -48,6 +50,7 @@ func NewGroupMemberHandler() GroupMemberHandler {
 		groupDao:         dao.NewGroupDao(model.GetDB(), cache.NewGroupCache(model.GetCacheType())),
 		db:               model.GetDB(),
 		redis:            model.GetRedisCli(),
+		publisher:        bus.Get(),
 		talkRecordCache:  cache.NewTalkRecordsCache(model.GetCacheType()),
 	}
 }
@@ -199,27 +202,18 @@ func (g groupMemberHandler) removeMember(ctx context.Context, params *types.Grou
 	g.groupMemberCache.BatchDelGroupRelation(ctx, membersIDs, int(params.GroupID))
 
 	// 广播网关将在线的用户加入房间
-	_, _ = g.redis.Pipelined(ctx, func(pipe redis.Pipeliner) error {
-		pipe.Publish(ctx, constant.ImTopicChat, jsonutil.Encode(map[string]any{
-			"event": constant.SubEventGroupJoin,
-			"data": jsonutil.Encode(map[string]any{
-				"type":     2,
-				"group_id": params.GroupID,
-				"uids":     membersIDs,
-			}),
-		}))
-
-		pipe.Publish(ctx, constant.ImTopicChat, jsonutil.Encode(map[string]any{
-			"event": constant.SubEventImMessage,
-			"data": jsonutil.Encode(map[string]any{
-				"sender_id":   int64(record.UserID),
-				"receiver_id": int64(record.ReceiverID),
-				"talk_type":   record.TalkType,
-				"msg_id":      record.MsgID,
-			}),
-		}))
-		return nil
-	})
+	_ = g.publisher.Publish(ctx, constant.ImTopicChat, bus.NewMessage(constant.SubEventGroupJoin, map[string]any{
+		"type":     2,
+		"group_id": params.GroupID,
+		"uids":     membersIDs,
+	}))
+
+	_ = g.publisher.Publish(ctx, constant.ImTopicChat, bus.NewMessage(constant.SubEventImMessage, map[string]any{
+		"sender_id":   int64(record.UserID),
+		"receiver_id": int64(record.ReceiverID),
+		"talk_type":   record.TalkType,
+		"msg_id":      record.MsgID,
+	}))
 
 	return nil
 
@@ -0,0 +1,135 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/zhufuyi/sponge/pkg/gin/middleware"
+	"github.com/zhufuyi/sponge/pkg/logger"
+	"lingua_exchange/pkg/socket"
+	"lingua_exchange/pkg/socket/adapter"
+)
+
+// 长轮询单次请求的最长等待时间
+const pollWaitTimeout = 25 * time.Second
+
+// httpCodec HTTP 降级传输仅支持 JSON
+var httpCodec, _ = socket.Codec(socket.CodecJSON)
+
+// Sse 通过 Server-Sent Events 接收消息，首条 session 事件为发送消息使用的会话ID
+func (m messageHandler) Sse(ctx *gin.Context) {
+	auth, err := m.authorize(ctx)
+	if err != nil {
+		logger.Error("im sse connection error", logger.Err(err), middleware.GCtxRequestIDField(ctx))
+		return
+	}
+
+	conn := adapter.NewHttpAdapter(adapter.NetworkSse, auth.uid)
+	defer conn.Close()
+
+	// 重投的未确认消息可能超过缓冲区，需先开始推送
+	go m.httpClient(ctx, auth, conn)
+
+	// SSE 为长连接，取消服务端写超时
+	_ = http.NewResponseController(ctx.Writer).SetWriteDeadline(time.Time{})
+
+	ctx.Header("Content-Type", "text/event-stream")
+	ctx.Header("Cache-Control", "no-cache")
+	ctx.Header("X-Accel-Buffering", "no")
+	ctx.Status(http.StatusOK)
+
+	_, _ = fmt.Fprintf(ctx.Writer, "event: session\ndata: %s\n\n", conn.Id())
+	ctx.Writer.Flush()
+
+	for {
+		data, err := conn.Next(ctx.Request.Context())
+		if err != nil {
+			return
+		}
+
+		if _, err := fmt.Fprintf(ctx.Writer, "data: %s\n\n", data); err != nil {
+			return
+		}
+		ctx.Writer.Flush()
+	}
+}
+
+// Poll 长轮询接收消息，未携带 session 时创建会话
+func (m messageHandler) Poll(ctx *gin.Context) {
+	auth, err := m.authorize(ctx)
+	if err != nil {
+		logger.Error("im poll connection error", logger.Err(err), middleware.GCtxRequestIDField(ctx))
+		return
+	}
+
+	var conn *adapter.HttpAdapter
+	if id := ctx.Query("session"); id != "" {
+		var ok bool
+		if conn, ok = adapter.HttpSession(id, auth.uid); !ok {
+			ctx.AbortWithStatusJSON(http.StatusGone, gin.H{"error": adapter.ErrHttpClosed.Error()})
+			return
+		}
+	} else {
+		conn = adapter.NewHttpAdapter(adapter.NetworkPoll, auth.uid)
+		go m.httpClient(ctx, auth, conn)
+	}
+
+	items, err := conn.Poll(ctx.Request.Context(), pollWaitTimeout)
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusGone, gin.H{"error": err.Error()})
+		return
+	}
+
+	messages := make([]json.RawMessage, 0, len(items))
+	for _, item := range items {
+		messages = append(messages, item)
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"session": conn.Id(), "messages": messages})
+}
+
+// Send 发送消息，请求体与 WebSocket 上行消息一致
+func (m messageHandler) Send(ctx *gin.Context) {
+	auth, err := m.authorize(ctx)
+	if err != nil {
+		return
+	}
+
+	conn, ok := adapter.HttpSession(ctx.Query("session"), auth.uid)
+	if !ok {
+		ctx.AbortWithStatusJSON(http.StatusGone, gin.H{"error": adapter.ErrHttpClosed.Error()})
+		return
+	}
+
+	data, err := io.ReadAll(http.MaxBytesReader(ctx.Writer, ctx.Request.Body, m.upgrader.ReadLimit()))
+	if err != nil {
+		var maxErr *http.MaxBytesError
+		if errors.As(err, &maxErr) {
+			ctx.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, gin.H{"error": err.Error()})
+			return
+		}
+
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := conn.Deliver(ctx.Request.Context(), data); err != nil {
+		ctx.AbortWithStatusJSON(http.StatusGone, gin.H{"error": err.Error()})
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// httpClient 创建 HTTP 降级传输的客户端，与 WebSocket 共用生命周期、心跳及 ACK
+func (m messageHandler) httpClient(ctx *gin.Context, auth *connAuth, conn *adapter.HttpAdapter) {
+	if err := m.newClient(auth, conn, httpCodec); err != nil {
+		logger.Error("im http connection error", logger.Err(err), middleware.GCtxRequestIDField(ctx))
+		_ = conn.Close()
+	}
+}
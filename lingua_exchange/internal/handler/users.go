@@ -16,7 +16,9 @@ import (
 	"github.com/zhufuyi/sponge/pkg/logger"
 	"github.com/zhufuyi/sponge/pkg/utils"
 	"gorm.io/gorm"
+	"lingua_exchange/internal/bus"
 	"lingua_exchange/internal/cache"
+	"lingua_exchange/internal/constant"
 	"lingua_exchange/internal/dao"
 	"lingua_exchange/internal/ecode"
 	"lingua_exchange/internal/model"
@@ -30,6 +32,9 @@ var _ UsersHandler = (*usersHandler)(nil)
 
 const (
 	GooglePlatform = "google"
+
+	// 账号封禁状态，封禁后注销令牌并断开长连接
+	userStatusBanned = "banned"
 )
 
 // UsersHandler defining the handler interface
@@ -48,6 +53,7 @@ type UsersHandler interface {
 	LoginFromEmail(c *gin.Context)
 	SignUpFromEmail(c *gin.Context)
 	ResetPassword(c *gin.Context)
+	LogoutAll(c *gin.Context)
 	UpdateUserInfoByID(c *gin.Context)
 }
 
@@ -59,6 +65,7 @@ type usersHandler struct {
 	globalConfigCache cache.GlobalConfigCache
 	userInterestsDao  dao.UserInterestsDao
 	userLanguagesDao  dao.UserLanguagesDao
+	publisher         bus.Publisher
 }
 
 // NewUsersHandler creating the handler interface
@@ -74,6 +81,7 @@ func NewUsersHandler() UsersHandler {
 		globalConfigCache: cache.NewGlobalConfigCache(model.GetCacheType()),
 		userInterestsDao:  dao.NewUserInterestsDao(model.GetDB(), cache.NewUserInterestsCache(model.GetCacheType())),
 		userLanguagesDao:  dao.NewUserLanguagesDao(model.GetDB(), cache.NewUserLanguagesCache(model.GetCacheType())),
+		publisher:         bus.Get(),
 	}
 }
 
@@ -118,6 +126,36 @@ func (h *usersHandler) ResetPassword(c *gin.Context) {
 		return
 	}
 
+	// 重置密码后已登录的设备需重新登录
+	if err := signOutClient(c, h.publisher, int(user.ID), constant.KickReasonPasswordReset); err != nil {
+		logger.Warn("failed to sign out user", logger.Err(err), middleware.GCtxRequestIDField(c))
+	}
+
+	response.Success(c)
+}
+
+// LogoutAll
+// @Summary logout all devices
+// @Description  revoke all tokens of the current user and close its socket connections
+// @Tags  Login
+// @Produce json
+// @Param Authorization header string true "Authorization"
+// @Success 200 {object} types.Result{}
+// @Router  /api/v1/users/logoutAll [post]
+// @Security BearerAuth
+func (h *usersHandler) LogoutAll(c *gin.Context) {
+	uid, err := jwt.HeaderObtainUID(c)
+	if err != nil {
+		response.Error(c, ecode.Unauthorized)
+		return
+	}
+
+	if err := signOutClient(c, h.publisher, uid, constant.KickReasonLogout); err != nil {
+		logger.Warn("failed to sign out user", logger.Err(err), middleware.GCtxRequestIDField(c))
+		response.Error(c, ecode.InternalServerError)
+		return
+	}
+
 	response.Success(c)
 }
 
@@ -623,8 +661,11 @@ func (h *usersHandler) UpdateUserInfoByID(c *gin.Context) {
 
 	if err != nil {
 		h.handleError(c, err, ecode.ErrUpdateByIDUsers)
+		return
 	}
 
+	h.signOutBanned(c, users)
+
 	response.Success(c, gin.H{"user": users})
 
 }
@@ -672,9 +713,22 @@ func (h *usersHandler) UpdateByID(c *gin.Context) {
 		return
 	}
 
+	h.signOutBanned(c, users)
+
 	response.Success(c)
 }
 
+// signOutBanned 账号被封禁时注销令牌并断开长连接
+func (h *usersHandler) signOutBanned(c *gin.Context, users *model.Users) {
+	if users.Status != userStatusBanned {
+		return
+	}
+
+	if err := signOutClient(c, h.publisher, int(users.ID), constant.KickReasonBanned); err != nil {
+		logger.Warn("failed to sign out banned user", logger.Err(err), logger.Any("id", users.ID), middleware.GCtxRequestIDField(c))
+	}
+}
+
 // GetByID get a record by id
 // @Summary get users detail
 // @Description get users detail by id
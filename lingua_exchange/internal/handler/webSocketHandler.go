@@ -1,16 +1,32 @@
 package handler
 
 import (
+	"context"
+	"fmt"
 	"log"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"github.com/zhufuyi/sponge/pkg/errcode"
 	"github.com/zhufuyi/sponge/pkg/gin/middleware"
+	"github.com/zhufuyi/sponge/pkg/gin/response"
 	"github.com/zhufuyi/sponge/pkg/logger"
+	"lingua_exchange/internal/bus"
 	"lingua_exchange/internal/cache"
 	"lingua_exchange/internal/chat/event"
 	"lingua_exchange/internal/config"
+	"lingua_exchange/internal/constant"
 	"lingua_exchange/internal/dao"
+	"lingua_exchange/internal/ecode"
+	"lingua_exchange/internal/imService"
 	"lingua_exchange/internal/model"
+	"lingua_exchange/internal/types"
+	"lingua_exchange/pkg/jsonutil"
 	"lingua_exchange/pkg/jwt"
 	"lingua_exchange/pkg/socket"
 	"lingua_exchange/pkg/socket/adapter"
@@ -19,13 +35,30 @@ import (
 
 var _ MessageHandler = (*messageHandler)(nil)
 
+// TCP 连接等待授权首帧的超时时间
+const tcpAuthorizeTimeout = 10 * time.Second
+
 type MessageHandler interface {
 	Connection(ctx *gin.Context)
+	TcpConnection(conn net.Conn)
+	Undelivered(ctx *gin.Context)
+	Detail(ctx *gin.Context)
+	ClusterNodes(ctx *gin.Context)
+	ClusterSessions(ctx *gin.Context)
+	ClusterKick(ctx *gin.Context)
+	Sse(ctx *gin.Context)
+	Poll(ctx *gin.Context)
+	Send(ctx *gin.Context)
 }
 
 type messageHandler struct {
 	messageCache *cache.MessageCache
+	serverCache  cache.ServerCache
+	ackCache     cache.AckCache
 	event        *event.ChatEvent
+	upgrader     *adapter.WsUpgrader
+	rateLimit    *socket.RateLimitOptions
+	devices      *devicePolicy
 }
 
 func (m messageHandler) Connection(ctx *gin.Context) {
@@ -35,44 +68,315 @@ func (m messageHandler) Connection(ctx *gin.Context) {
 	}
 }
 
+// Undelivered 获取用户未送达消息数(各设备中积压最多的一份)，指定 uid 时返回单个用户，否则返回未送达数不少于 min 的用户
+func (m messageHandler) Undelivered(ctx *gin.Context) {
+	if uid, err := strconv.Atoi(ctx.Query("uid")); err == nil {
+		response.Success(ctx, gin.H{"uid": uid, "count": m.ackCache.Count(ctx, uid)})
+		return
+	}
+
+	minCount, _ := strconv.ParseInt(ctx.DefaultQuery("min", "1"), 10, 64)
+	limit, _ := strconv.ParseInt(ctx.DefaultQuery("limit", "100"), 10, 64)
+
+	users, err := m.ackCache.Users(ctx, max(minCount, 1), min(max(limit, 1), 1000))
+	if err != nil {
+		response.Error(ctx, ecode.InternalServerError, err)
+		return
+	}
+
+	response.Success(ctx, gin.H{"users": users})
+}
+
+// Detail 获取各渠道在本节点的连接数
+func (m messageHandler) Detail(ctx *gin.Context) {
+	detail := make(map[string]any)
+	for _, ch := range socket.Session.Channels() {
+		detail[ch.Name()] = ch.Count()
+	}
+
+	ctx.JSON(http.StatusOK, detail)
+}
+
+// connAuth 连接授权信息
+type connAuth struct {
+	uid        int
+	channel    socket.IChannel
+	expireAt   time.Time
+	remoteAddr string
+	platform   string
+	device     string
+}
+
+// authorize 校验连接地址及令牌，失败时已回写错误信息
+func (m messageHandler) authorize(ctx *gin.Context) (*connAuth, error) {
+	// 连接地址为 /ws/{channel}.io
+	name, ok := strings.CutSuffix(ctx.Param("channel"), ".io")
+	if !ok {
+		ctx.AbortWithStatusJSON(http.StatusNotFound, gin.H{"msg": "请求地址不存在"})
+		return nil, fmt.Errorf("invalid socket path %s", ctx.Request.URL.Path)
+	}
+
+	channel, ok := socket.Session.Channel(name)
+	if !ok {
+		ctx.AbortWithStatusJSON(http.StatusNotFound, gin.H{"error": ecode.ErrSocketChannelNotFound.Msg()})
+		return nil, fmt.Errorf("channel %s not found", name)
+	}
+
+	// 用户身份只能来源于已校验的令牌
+	id, err := jwt.WSUserId(ctx)
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return nil, err
+	}
+
+	expireAt, err := jwt.TokenExpireAt(ctx, jwt.WSToken(ctx))
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return nil, err
+	}
+
+	return &connAuth{
+		uid:        id,
+		channel:    channel,
+		expireAt:   expireAt,
+		remoteAddr: ctx.ClientIP(),
+		platform:   jwt.HeaderPlatform(ctx),
+		device:     jwt.HeaderDeviceToken(ctx),
+	}, nil
+}
+
 func (m messageHandler) conn(ctx *gin.Context) error {
-	conn, err := adapter.NewWsAdapter(ctx.Writer, ctx.Request)
+	auth, err := m.authorize(ctx)
+	if err != nil {
+		return err
+	}
+
+	codec, err := m.codec(ctx)
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": ecode.ErrSocketCodecNotSupported.Msg()})
+		return err
+	}
+
+	conn, err := m.upgrader.Upgrade(ctx.Writer, ctx.Request)
 	if err != nil {
 		log.Printf("websocket connect error: %s", err.Error())
 		return err
 	}
-	uid := jwt.ParseWSUrlUserId(ctx)
+	conn.SetBinary(codec.Binary())
+
+	return m.newClient(auth, conn, codec)
+}
+
+// codec 协商消息编码格式，优先使用 WebSocket 子协议，其次为 codec 查询参数
+func (m messageHandler) codec(ctx *gin.Context) (socket.ICodec, error) {
+	for _, name := range websocket.Subprotocols(ctx.Request) {
+		if codec, ok := socket.Codec(name); ok {
+			ctx.Writer.Header().Set("Sec-WebSocket-Protocol", name)
+			return codec, nil
+		}
+	}
+
+	codec, ok := socket.Codec(ctx.Query("codec"))
+	if !ok {
+		return nil, fmt.Errorf("codec %s not supported", ctx.Query("codec"))
+	}
+
+	return codec, nil
+}
+
+// TcpConnection TCP 长连接接入，首帧必须为授权信息
+func (m messageHandler) TcpConnection(conn net.Conn) {
+	if err := m.tcpConn(conn); err != nil {
+		logger.Error("im tcp connection error", logger.Err(err), logger.String("addr", conn.RemoteAddr().String()))
+		_ = conn.Close()
+	}
+}
+
+func (m messageHandler) tcpConn(conn net.Conn) error {
+	tcpConn, err := adapter.NewTcpAdapter(conn)
+	if err != nil {
+		return err
+	}
+
+	_ = conn.SetReadDeadline(time.Now().Add(tcpAuthorizeTimeout))
+	data, err := tcpConn.Read()
+	if err != nil {
+		return err
+	}
+	_ = conn.SetReadDeadline(time.Time{})
+
+	var auth types.TcpAuthorize
+	if err := jsonutil.Decode(data, &auth); err != nil {
+		m.tcpReject(tcpConn, ecode.ErrSocketUnauthorized)
+		return err
+	}
+
+	uid, err := jwt.VerifyToken(context.Background(), auth.Token)
+	if err != nil {
+		m.tcpReject(tcpConn, ecode.ErrSocketUnauthorized)
+		return err
+	}
+
+	expireAt, err := jwt.TokenExpireAt(context.Background(), auth.Token)
+	if err != nil {
+		m.tcpReject(tcpConn, ecode.ErrSocketUnauthorized)
+		return err
+	}
+
 	id, err := strutil.StringToInt(uid)
 	if err != nil {
+		m.tcpReject(tcpConn, ecode.ErrSocketUnauthorized)
 		return err
 	}
-	return m.newClient(id, conn)
+
+	if auth.Channel == "" {
+		auth.Channel = socket.Session.Chat.Name()
+	}
+
+	channel, ok := socket.Session.Channel(auth.Channel)
+	if !ok {
+		m.tcpReject(tcpConn, ecode.ErrSocketChannelNotFound)
+		return fmt.Errorf("channel %s not found", auth.Channel)
+	}
+
+	codec, ok := socket.Codec(auth.Codec)
+	if !ok {
+		m.tcpReject(tcpConn, ecode.ErrSocketCodecNotSupported)
+		return fmt.Errorf("codec %s not supported", auth.Codec)
+	}
+
+	return m.newClient(&connAuth{
+		uid:        id,
+		channel:    channel,
+		expireAt:   expireAt,
+		remoteAddr: conn.RemoteAddr().String(),
+		platform:   auth.Platform,
+		device:     auth.Device,
+	}, tcpConn, codec)
 }
 
-func (m messageHandler) newClient(uid int, conn socket.IConn) error {
+// tcpReject 授权失败时回写错误信息
+func (m messageHandler) tcpReject(conn socket.IConn, e *errcode.Error) {
+	_ = conn.Write(jsonutil.Marshal(&socket.ClientResponse{
+		Event: constant.PushEventError,
+		Content: &types.EventReply{
+			Code: e.Code(),
+			Msg:  e.Msg(),
+		},
+	}))
+}
+
+func (m messageHandler) newClient(auth *connAuth, conn socket.IConn, codec socket.ICodec) error {
+	conf := config.Get().Socket
+	ev := m.channelEvent(auth.channel.Name())
+
 	return socket.NewClient(conn, &socket.ClientOption{
-		Uid:     uid,
-		Channel: socket.Session.Chat,
-		Storage: m.messageCache,
-		Buffer:  10,
+		Uid:            auth.uid,
+		Channel:        auth.channel,
+		RemoteAddr:     auth.remoteAddr,
+		Platform:       auth.platform,
+		Device:         auth.device,
+		Storage:        m.messageCache,
+		AckStorage:     m.ackCache,
+		Buffer:         conf.Buffer,
+		OverflowPolicy: socket.OverflowPolicy(conf.OverflowPolicy),
+		MaxOverflow:    conf.MaxOverflow,
+		WriteTimeout:   time.Duration(conf.WriteTimeout) * time.Second,
+		Codec:          codec,
+		RateLimit:      m.rateLimit,
 	}, socket.NewEvent(
 		// 连接成功回调
-		socket.WithOpenEvent(m.event.OnOpen),
+		socket.WithOpenEvent(func(client socket.IClient) {
+			ev.OnOpen(client)
+			ev.WatchToken(client, auth.expireAt)
+			go m.devices.takeover(client)
+		}),
 		// 接收消息回调
-		socket.WithMessageEvent(m.event.OnMessage),
+		socket.WithMessageEvent(ev.OnMessage),
 		// 关闭连接回调
-		socket.WithCloseEvent(m.event.OnClose),
+		socket.WithCloseEvent(ev.OnClose),
 	))
 }
 
 func NewMessageHandler() MessageHandler {
+	messageCache := cache.NewMessageCache(model.GetCacheType())
+
 	chatEvent := &event.ChatEvent{
-		Redis:           model.GetRedisCli(),
-		Config:          config.Get(),
-		RoomStorage:     cache.NewChatRoomCache(model.GetCacheType()),
-		GroupMemberRepo: dao.NewGroupMemberDao(model.GetDB(), cache.NewGroupMemberCache(model.GetCacheType()))}
+		Redis:             model.GetRedisCli(),
+		DB:                model.GetDB(),
+		Config:            config.Get(),
+		RoomStorage:       cache.NewChatRoomCache(model.GetCacheType()),
+		GroupMemberRepo:   dao.NewGroupMemberDao(model.GetDB(), cache.NewGroupMemberCache(model.GetCacheType())),
+		TalkRecordsDao:    dao.NewTalkRecordsDao(model.GetDB(), cache.NewTalkRecordsCache(model.GetCacheType())),
+		TalkSessionDao:    dao.NewTalkSessionDao(model.GetDB()),
+		UnreadCache:       cache.NewUnreadCache(),
+		MessageCache:      messageCache,
+		MessageService:    imService.NewMessageService(),
+		PermissionService: imService.NewPermissionService(),
+		Publisher:         bus.Get(),
+	}
 
-	messageCache := cache.NewMessageCache(model.GetCacheType())
+	conf := config.Get().Socket.Websocket
+	upgrader := adapter.NewWsUpgrader(&adapter.WsOptions{
+		ReadBufferSize:       conf.ReadBufferSize,
+		WriteBufferSize:      conf.WriteBufferSize,
+		ReadLimit:            conf.ReadLimit,
+		Compression:          conf.Compression,
+		CompressionLevel:     conf.CompressionLevel,
+		CompressionThreshold: conf.CompressionThreshold,
+		AllowedOrigins:       conf.AllowedOrigins,
+	})
+
+	return &messageHandler{
+		messageCache: messageCache,
+		serverCache:  cache.NewServerCache(model.GetCacheType()),
+		ackCache:     cache.NewAckCache(),
+		event:        chatEvent,
+		upgrader:     upgrader,
+		rateLimit:    newRateLimitOptions(config.Get().Socket.RateLimit),
+		devices:      newDevicePolicy(config.Get().Socket.DevicePolicy, config.Get().App.Sid, messageCache, chatEvent.Publisher),
+	}
+}
+
+// newRateLimitOptions 上行消息限流配置，未启用时返回空
+func newRateLimitOptions(conf config.SocketRateLimit) *socket.RateLimitOptions {
+	if !conf.Enable {
+		return nil
+	}
+
+	limiter := &userRateLimiter{cache: cache.NewRateLimitCache(model.GetRedisCli()), rules: make(map[string]config.SocketRateRule)}
+	options := &socket.RateLimitOptions{
+		MaxFrameSize: conf.MaxFrameSize,
+		Rules:        make(map[string]socket.RateRule),
+		WarnAfter:    conf.WarnAfter,
+		CloseAfter:   conf.CloseAfter,
+		UserLimiter:  limiter,
+	}
+
+	for _, rule := range conf.Rules {
+		options.Rules[rule.Event] = socket.RateRule{Rate: rule.Rate, Burst: rule.Burst}
+		limiter.rules[rule.Event] = rule
+	}
+
+	return options
+}
+
+// userRateLimiter 基于 Redis 的用户级限流，多节点共享
+type userRateLimiter struct {
+	cache cache.RateLimitCache
+	rules map[string]config.SocketRateRule
+}
+
+// Allow 未配置规则的事件共用 * 令牌桶
+func (u *userRateLimiter) Allow(ctx context.Context, uid int, event string) (bool, error) {
+	rule, ok := u.rules[event]
+	if !ok {
+		event, rule = "*", u.rules["*"]
+	}
+
+	if rule.UserRate <= 0 {
+		return true, nil
+	}
 
-	return &messageHandler{messageCache: messageCache, event: chatEvent}
+	return u.cache.Allow(ctx, uid, event, rule.UserRate, rule.UserBurst)
 }
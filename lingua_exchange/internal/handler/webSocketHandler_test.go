@@ -0,0 +1,40 @@
+package handler
+
+import (
+	"context"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"lingua_exchange/internal/cache"
+	"lingua_exchange/internal/config"
+)
+
+func Test_userRateLimiter_Allow(t *testing.T) {
+	mr := miniredis.RunT(t)
+	rds := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	defer rds.Close()
+
+	ctx := context.Background()
+	limiter := &userRateLimiter{cache: cache.NewRateLimitCache(rds), rules: map[string]config.SocketRateRule{
+		"*":                   {Event: "*", UserRate: 0.001, UserBurst: 1},
+		"im.message.keyboard": {Event: "im.message.keyboard", UserRate: 0.001, UserBurst: 1},
+	}}
+
+	// 未配置规则的事件共用 * 令牌桶
+	ok, err := limiter.Allow(ctx, 1, "a")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	ok, err = limiter.Allow(ctx, 1, "b")
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	ok, err = limiter.Allow(ctx, 1, "im.message.keyboard")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	assert.True(t, mr.Exists("ws:rate:1:*"))
+	assert.False(t, mr.Exists("ws:rate:1:a"))
+}
@@ -0,0 +1,139 @@
+package imService
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/zhufuyi/sponge/pkg/logger"
+	"lingua_exchange/internal/bus"
+	"lingua_exchange/internal/cache"
+	"lingua_exchange/internal/constant"
+	"lingua_exchange/internal/dao"
+	"lingua_exchange/internal/model"
+	"lingua_exchange/internal/types"
+	"lingua_exchange/pkg/jsonutil"
+)
+
+const (
+	// 发件箱消息写入后超过该时间仍未投递，由补偿任务重新投递
+	outboxRelayDelay = 10 * time.Second
+	// 单次补偿投递数量
+	outboxRelayLimit = 100
+	// 最大重试次数
+	outboxMaxRetry = 10
+	// 已投递及投递失败的消息保留时长，超过后由补偿任务清理
+	outboxRetention = 24 * time.Hour
+	// 单次清理数量
+	outboxPurgeLimit = 1000
+)
+
+// 消息推送发件箱
+var _ IOutboxService = (*outboxService)(nil)
+
+type IOutboxService interface {
+	// NewTalkMessage 构建对话消息推送
+	NewTalkMessage(record *model.TalkRecords) *model.TalkMessageOutbox
+	// Publish 推送消息并标记为已投递，失败时保留待补偿投递
+	Publish(ctx context.Context, outbox *model.TalkMessageOutbox) error
+	// Relay 补偿投递超时未投递的消息，并清理超过保留时长的消息
+	Relay(ctx context.Context) error
+}
+
+type outboxService struct {
+	outboxDao    dao.TalkMessageOutboxDao
+	publisher    bus.Publisher
+	messageCache *cache.MessageCache
+	serverCache  cache.ServerCache
+}
+
+func NewOutboxService() IOutboxService {
+	return &outboxService{
+		outboxDao:    dao.NewTalkMessageOutboxDao(model.GetDB()),
+		publisher:    bus.Get(),
+		messageCache: cache.NewMessageCache(model.GetCacheType()),
+		serverCache:  cache.NewServerCache(model.GetCacheType()),
+	}
+}
+
+func (o *outboxService) NewTalkMessage(record *model.TalkRecords) *model.TalkMessageOutbox {
+	return &model.TalkMessageOutbox{
+		MsgID:      record.MsgID,
+		TalkType:   record.TalkType,
+		UserID:     record.UserID,
+		ReceiverID: record.ReceiverID,
+		Event:      constant.SubEventImMessage,
+		Payload: jsonutil.Encode(bus.NewMessage(constant.SubEventImMessage, map[string]any{
+			"sender_id":   record.UserID,
+			"receiver_id": record.ReceiverID,
+			"talk_type":   record.TalkType,
+			"msg_id":      record.MsgID,
+		})),
+	}
+}
+
+func (o *outboxService) Publish(ctx context.Context, outbox *model.TalkMessageOutbox) error {
+	if err := o.publish(ctx, outbox); err != nil {
+		_ = o.outboxDao.IncrRetry(ctx, outbox.ID, outboxMaxRetry)
+		return err
+	}
+
+	return o.outboxDao.MarkPublished(ctx, outbox.ID)
+}
+
+func (o *outboxService) Relay(ctx context.Context) error {
+	items, err := o.outboxDao.FindPending(ctx, time.Now().Add(-outboxRelayDelay), outboxRelayLimit)
+	if err != nil {
+		return err
+	}
+
+	for _, item := range items {
+		if err := o.Publish(ctx, item); err != nil {
+			logger.Errorf("[Outbox] relay msg_id:%s err: %s", item.MsgID, err.Error())
+		}
+	}
+
+	if _, err := o.outboxDao.Purge(ctx, time.Now().Add(-outboxRetention), outboxPurgeLimit); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// publish 私信消息在节点较多时只投递到用户在线的节点
+// 部分节点投递失败时整条消息会重新投递，已收到的节点由消费端按消息ID去重
+func (o *outboxService) publish(ctx context.Context, outbox *model.TalkMessageOutbox) error {
+	var msg types.SubscribeContent
+	if err := jsonutil.Decode(outbox.Payload, &msg); err != nil {
+		return err
+	}
+
+	if outbox.TalkType == constant.ChatPrivateMode {
+		sids := o.serverCache.All(ctx, 1)
+
+		if len(sids) > 3 {
+			for _, sid := range sids {
+				for _, uid := range []int{outbox.UserID, outbox.ReceiverID} {
+					if !o.messageCache.IsCurrentServerOnline(ctx, sid, constant.ImChannelChat, strconv.Itoa(uid)) {
+						continue
+					}
+
+					if err := o.publisher.Publish(ctx, fmt.Sprintf(constant.ImTopicChatPrivate, sid), &msg); err != nil {
+						return err
+					}
+
+					break
+				}
+			}
+
+			return nil
+		}
+	}
+
+	if err := o.publisher.Publish(ctx, constant.ImTopicChat, &msg); err != nil {
+		return fmt.Errorf("[ALL]消息推送失败 %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,139 @@
+package imService
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/zhufuyi/sponge/pkg/gotest"
+	"lingua_exchange/internal/cache"
+	"lingua_exchange/internal/constant"
+	"lingua_exchange/internal/dao"
+	"lingua_exchange/internal/model"
+	"lingua_exchange/internal/types"
+	"lingua_exchange/pkg/jsonutil"
+)
+
+// fakePublisher 记录发布的消息，err 不为空时发布失败
+type fakePublisher struct {
+	topics []string
+	events []string
+	err    error
+}
+
+func (f *fakePublisher) Publish(_ context.Context, topic string, msg *types.SubscribeContent) error {
+	if f.err != nil {
+		return f.err
+	}
+
+	f.topics = append(f.topics, topic)
+	f.events = append(f.events, msg.Event)
+	return nil
+}
+
+type fakeServerCache struct {
+	cache.ServerCache
+}
+
+func (f *fakeServerCache) All(context.Context, int) []string {
+	return []string{"node-1"}
+}
+
+func newOutboxTest(t *testing.T) (*gotest.Dao, *outboxService, *fakePublisher) {
+	d := gotest.NewDao(nil, nil)
+	t.Cleanup(d.Close)
+
+	publisher := &fakePublisher{}
+	return d, &outboxService{
+		outboxDao:   dao.NewTalkMessageOutboxDao(d.DB),
+		publisher:   publisher,
+		serverCache: &fakeServerCache{},
+	}, publisher
+}
+
+func TestMessageService_create(t *testing.T) {
+	d, outbox, _ := newOutboxTest(t)
+	m := &MessageService{
+		talkRecordsDao: dao.NewTalkRecordsDao(d.DB, nil),
+		outboxDao:      outbox.outboxDao,
+		outbox:         outbox,
+		db:             d.DB,
+	}
+	record := &model.TalkRecords{MsgID: "m1", TalkType: constant.ChatPrivateMode, UserID: 1, ReceiverID: 2, Extra: "{}"}
+
+	// 聊天记录与发件箱在同一事务中写入
+	d.SQLMock.ExpectBegin()
+	d.SQLMock.ExpectExec("INSERT INTO `talk_records`").WillReturnResult(sqlmock.NewResult(1, 1))
+	d.SQLMock.ExpectExec("INSERT INTO `talk_message_outbox`").WillReturnResult(sqlmock.NewResult(3, 1))
+	d.SQLMock.ExpectCommit()
+
+	item, err := m.create(context.Background(), record)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(3), item.ID)
+	assert.Equal(t, "m1", item.MsgID)
+	assert.Equal(t, constant.SubEventImMessage, item.Event)
+
+	// 发件箱写入失败时聊天记录一起回滚
+	d.SQLMock.ExpectBegin()
+	d.SQLMock.ExpectExec("INSERT INTO `talk_records`").WillReturnResult(sqlmock.NewResult(2, 1))
+	d.SQLMock.ExpectExec("INSERT INTO `talk_message_outbox`").WillReturnError(errors.New("no such table"))
+	d.SQLMock.ExpectRollback()
+
+	_, err = m.create(context.Background(), &model.TalkRecords{MsgID: "m2", Extra: "{}"})
+	assert.Error(t, err)
+	assert.NoError(t, d.SQLMock.ExpectationsWereMet())
+}
+
+func TestOutboxService_Publish(t *testing.T) {
+	d, o, publisher := newOutboxTest(t)
+	item := o.NewTalkMessage(&model.TalkRecords{MsgID: "m1", TalkType: constant.ChatPrivateMode, UserID: 1, ReceiverID: 2})
+	item.ID = 3
+
+	d.SQLMock.ExpectBegin()
+	d.SQLMock.ExpectExec("UPDATE `talk_message_outbox` SET .*`status`").WillReturnResult(sqlmock.NewResult(0, 1))
+	d.SQLMock.ExpectCommit()
+
+	assert.NoError(t, o.Publish(context.Background(), item))
+	assert.Equal(t, []string{constant.ImTopicChat}, publisher.topics)
+	assert.Equal(t, []string{constant.SubEventImMessage}, publisher.events)
+
+	// 推送失败时只增加重试次数，保留给补偿任务
+	publisher.err = errors.New("bus down")
+	d.SQLMock.ExpectBegin()
+	d.SQLMock.ExpectExec("UPDATE `talk_message_outbox` SET .*`retry`=retry \\+ 1").WillReturnResult(sqlmock.NewResult(0, 1))
+	d.SQLMock.ExpectCommit()
+
+	assert.Error(t, o.Publish(context.Background(), item))
+	assert.NoError(t, d.SQLMock.ExpectationsWereMet())
+}
+
+func TestOutboxService_Relay(t *testing.T) {
+	d, o, publisher := newOutboxTest(t)
+	item := o.NewTalkMessage(&model.TalkRecords{MsgID: "m1", TalkType: constant.ChatGroupMode, UserID: 1, ReceiverID: 9})
+
+	d.SQLMock.ExpectQuery("SELECT \\* FROM `talk_message_outbox` WHERE .*status = \\? and created_at < \\?").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "msg_id", "talk_type", "user_id", "receiver_id", "event", "payload", "status"}).
+			AddRow(5, item.MsgID, item.TalkType, item.UserID, item.ReceiverID, item.Event, item.Payload, model.OutboxStatusPending))
+	d.SQLMock.ExpectBegin()
+	d.SQLMock.ExpectExec("UPDATE `talk_message_outbox` SET .*`status`").
+		WithArgs(model.OutboxStatusPublished, sqlmock.AnyArg(), 5).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	d.SQLMock.ExpectCommit()
+
+	// 清理超过保留时长的已投递及投递失败消息
+	d.SQLMock.ExpectBegin()
+	d.SQLMock.ExpectExec("DELETE FROM `talk_message_outbox` WHERE status in \\(\\?,\\?\\) and created_at < \\? LIMIT 1000").
+		WithArgs(model.OutboxStatusPublished, model.OutboxStatusFailed, sqlmock.AnyArg()).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	d.SQLMock.ExpectCommit()
+
+	assert.NoError(t, o.Relay(context.Background()))
+	assert.Equal(t, []string{constant.ImTopicChat}, publisher.topics)
+
+	var msg types.SubscribeContent
+	assert.NoError(t, jsonutil.Decode(item.Payload, &msg))
+	assert.Equal(t, constant.SubEventImMessage, msg.Event)
+	assert.NoError(t, d.SQLMock.ExpectationsWereMet())
+}
@@ -4,13 +4,13 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"strconv"
 	"time"
 
 	"github.com/redis/go-redis/v9"
 	"github.com/zhufuyi/sponge/pkg/ggorm/query"
 	"github.com/zhufuyi/sponge/pkg/logger"
 	"gorm.io/gorm"
+	"lingua_exchange/internal/bus"
 	"lingua_exchange/internal/cache"
 	"lingua_exchange/internal/constant"
 	"lingua_exchange/internal/dao"
@@ -70,6 +70,10 @@ type MessageService struct {
 	groupMemberDao   dao.GroupMemberDao
 	messageCache     *cache.MessageCache
 	serverCache      cache.ServerCache
+	talkSessionDao   dao.TalkSessionDao
+	outboxDao        dao.TalkMessageOutboxDao
+	outbox           IOutboxService
+	publisher        bus.Publisher
 	db               *gorm.DB
 }
 
@@ -83,6 +87,10 @@ func NewMessageService() IMessageService {
 		groupMemberDao:   dao.NewGroupMemberDao(model.GetDB(), cache.NewGroupMemberCache(model.GetCacheType())),
 		messageCache:     cache.NewMessageCache(model.GetCacheType()),
 		serverCache:      cache.NewServerCache(model.GetCacheType()),
+		talkSessionDao:   dao.NewTalkSessionDao(model.GetDB()),
+		outboxDao:        dao.NewTalkMessageOutboxDao(model.GetDB()),
+		outbox:           NewOutboxService(),
+		publisher:        bus.Get(),
 		db:               model.GetDB(),
 	}
 }
@@ -290,14 +298,13 @@ func (m MessageService) Revoke(ctx *context.Context, uid int, msgId string) erro
 		Datetime: timeutil.DateTime(),
 	})
 
-	body := map[string]any{
-		"event": constant.SubEventImMessageRevoke,
-		"data": jsonutil.Encode(map[string]any{
-			"msg_id": record.MsgID,
-		}),
-	}
+	body := bus.NewMessage(constant.SubEventImMessageRevoke, map[string]any{
+		"msg_id": record.MsgID,
+	})
 
-	m.redis.Publish(ctx2, constant.ImTopicChat, jsonutil.Encode(body))
+	if err := m.publisher.Publish(ctx2, constant.ImTopicChat, body); err != nil {
+		logger.Errorf("MessageService revoke publish err: %s", err.Error())
+	}
 
 	return nil
 }
@@ -311,7 +318,7 @@ func (m MessageService) save(ctx *context.Context, data *model.TalkRecords) erro
 
 	m.loadSequence(ctx2, data)
 
-	err := m.talkRecordsDao.Create(ctx2, data)
+	outbox, err := m.create(ctx2, data)
 	if err != nil {
 		return err
 	}
@@ -341,8 +348,27 @@ func (m MessageService) save(ctx *context.Context, data *model.TalkRecords) erro
 		}
 	}
 
+	m.afterHandler(ctx2, data, lastMessage, outbox)
+
 	return nil
+}
+
+// create 聊天记录与推送发件箱在同一事务中写入
+func (m *MessageService) create(ctx context.Context, data *model.TalkRecords) (*model.TalkMessageOutbox, error) {
+	outbox := m.outbox.NewTalkMessage(data)
+	err := m.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if _, err := m.talkRecordsDao.CreateByTx(ctx, tx, data); err != nil {
+			return err
+		}
+
+		_, err := m.outboxDao.CreateByTx(ctx, tx, outbox)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
 
+	return outbox, nil
 }
 
 func (m *MessageService) loadReply(ctx context.Context, data *model.TalkRecords) {
@@ -414,7 +440,7 @@ func (m *MessageService) loadSequence(ctx context.Context, data *model.TalkRecor
 }
 
 // 消息发送完，后置处理
-func (m *MessageService) afterHandler(ctx context.Context, record *model.TalkRecords, opt types.TalkLastMessage) {
+func (m *MessageService) afterHandler(ctx context.Context, record *model.TalkRecords, opt types.TalkLastMessage, outbox *model.TalkMessageOutbox) {
 
 	if record.TalkType == constant.ChatPrivateMode {
 		m.unreadCache.Incr(ctx, constant.ChatPrivateMode, int(record.UserID), int(record.ReceiverID))
@@ -436,40 +462,29 @@ func (m *MessageService) afterHandler(ctx context.Context, record *model.TalkRec
 		Datetime: opt.CreatedAt,
 	})
 
-	content := jsonutil.Encode(map[string]any{
-		"event": constant.SubEventImMessage,
-		"data": jsonutil.Encode(map[string]any{
-			"sender_id":   record.UserID,
-			"receiver_id": record.ReceiverID,
-			"talk_type":   record.TalkType,
-			"msg_id":      record.MsgID,
-		}),
-	})
+	m.touchSession(ctx, record)
 
-	if record.TalkType == constant.ChatPrivateMode {
-		sids := m.serverCache.All(ctx, 1)
-
-		if len(sids) > 3 {
-			pipe := m.redis.Pipeline()
-
-			for _, sid := range sids {
-				for _, uid := range []int{record.UserID, record.ReceiverID} {
-					if !m.messageCache.IsCurrentServerOnline(ctx, sid, constant.ImChannelChat, strconv.Itoa(uid)) {
-						continue
-					}
-
-					pipe.Publish(ctx, fmt.Sprintf(constant.ImTopicChatPrivate, sid), content)
-				}
-			}
-
-			if _, err := pipe.Exec(ctx); err == nil {
-				return
-			}
-		}
+	// 推送失败时由发件箱补偿任务重新投递
+	if err := m.outbox.Publish(ctx, outbox); err != nil {
+		logger.Errorf("MessageService outbox publish msg_id:%s err: %s", record.MsgID, err.Error())
+	}
+}
 
+// touchSession 更新或创建双方的会话列表
+func (m *MessageService) touchSession(ctx context.Context, record *model.TalkRecords) {
+	if record.UserID > 0 {
+		m.talkSessionDao.BatchAddList(ctx, record.UserID, map[string]int{
+			fmt.Sprintf("%d_%d", record.TalkType, record.ReceiverID): 1,
+		})
 	}
 
-	if err := m.redis.Publish(ctx, constant.ImTopicChat, content).Err(); err != nil {
-		logger.Errorf("[ALL]消息推送失败 %s", err.Error())
+	if record.TalkType == constant.ChatPrivateMode {
+		m.talkSessionDao.BatchAddList(ctx, record.ReceiverID, map[string]int{
+			fmt.Sprintf("%d_%d", record.TalkType, record.UserID): 1,
+		})
+	} else if record.TalkType == constant.ChatGroupMode {
+		if err := m.talkSessionDao.Touch(ctx, constant.ChatGroupMode, record.ReceiverID); err != nil {
+			logger.Errorf("MessageService touch session err: %s", err.Error())
+		}
 	}
 }
@@ -0,0 +1,31 @@
+package model
+
+import (
+	"github.com/zhufuyi/sponge/pkg/ggorm"
+)
+
+// 发件箱投递状态
+const (
+	OutboxStatusPending   = 0 // 待投递
+	OutboxStatusPublished = 1 // 已投递
+	OutboxStatusFailed    = 2 // 超过重试次数
+)
+
+// TalkMessageOutbox 消息推送发件箱，与聊天记录在同一事务中写入，保证推送不丢失
+type TalkMessageOutbox struct {
+	ggorm.Model `gorm:"embedded"` // embed id and time
+
+	MsgID      string `gorm:"column:msg_id;type:varchar(64);NOT NULL" json:"msgID"`                          // 消息ID
+	TalkType   uint   `gorm:"column:talk_type;type:int(11) unsigned;default:1;NOT NULL" json:"talkType"`     // 对话类型[1:私信;2:群聊;]
+	UserID     int    `gorm:"column:user_id;type:bigint(20) unsigned;default:0;NOT NULL" json:"userID"`      // 发送者ID
+	ReceiverID int    `gorm:"column:receiver_id;type:int(11) unsigned;default:0;NOT NULL" json:"receiverID"` // 接收者ID（用户ID 或 群ID）
+	Event      string `gorm:"column:event;type:varchar(64);NOT NULL" json:"event"`                           // 订阅事件名
+	Payload    string `gorm:"column:payload;type:text;NOT NULL" json:"payload"`                              // 订阅消息内容
+	Status     uint   `gorm:"column:status;type:tinyint(4) unsigned;default:0;NOT NULL" json:"status"`       // 投递状态[0:待投递;1:已投递;2:投递失败;]
+	Retry      uint   `gorm:"column:retry;type:int(11) unsigned;default:0;NOT NULL" json:"retry"`            // 重试次数
+}
+
+// TableName table name
+func (m *TalkMessageOutbox) TableName() string {
+	return "talk_message_outbox"
+}
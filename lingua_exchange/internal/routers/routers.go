@@ -17,7 +17,6 @@ import (
 	"github.com/zhufuyi/sponge/pkg/gin/middleware/metrics"
 	"github.com/zhufuyi/sponge/pkg/gin/prof"
 	"github.com/zhufuyi/sponge/pkg/gin/validator"
-	"github.com/zhufuyi/sponge/pkg/jwt"
 	"github.com/zhufuyi/sponge/pkg/logger"
 	"lingua_exchange/docs"
 	"lingua_exchange/internal/config"
@@ -53,11 +52,7 @@ func NewRouter() *gin.Engine {
 	))
 
 	// init jwt middleware
-	jwt.Init(
-		jwt.WithExpire(verify.UserTokenExpireTime),
-		jwt.WithSigningKey("live_lingua:"),
-		jwt.WithSigningMethod(jwt.HS384),
-	)
+	verify.Init()
 
 	// metrics middleware
 	if config.Get().App.EnableMetrics {
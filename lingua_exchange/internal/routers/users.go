@@ -24,6 +24,7 @@ func usersRouter(group *gin.RouterGroup, h handler.UsersHandler) {
 	g.POST("/loginFromEmail", h.LoginFromEmail)                              // [post] /api/v1/LoginFromEmail
 	g.POST("/signUpFromEmail", h.SignUpFromEmail)                            // [post] /api/v1/signUpFromEmail
 	g.POST("/resetPassword", h.ResetPassword)                                // [post] /api/v1/resetPassword
+	g.POST("/logoutAll", jwt.AuthMiddleware(), h.LogoutAll)                  // [post] /api/v1/users/logoutAll
 	g.PUT("/updateUserInfo/:id", h.UpdateUserInfoByID, jwt.AuthMiddleware()) // [put] /api/v1/users/:id
 
 	g.POST("/", h.Create, jwt.AuthMiddleware())          // [post] /api/v1/users
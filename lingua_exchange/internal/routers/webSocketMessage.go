@@ -1,39 +1,76 @@
 package handler
 
 import (
+	"crypto/subtle"
 	"log"
 	"net/http"
+	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/zhufuyi/sponge/pkg/gin/middleware/metrics"
+	"lingua_exchange/internal/config"
 	"lingua_exchange/internal/handler"
 	verify "lingua_exchange/pkg/jwt"
-	"lingua_exchange/pkg/socket"
 )
 
-func NewWebSocketRouter() *gin.Engine {
+func NewWebSocketRouter(h handler.MessageHandler) *gin.Engine {
 	r := gin.New()
 
 	r.Use(gin.RecoveryWithWriter(gin.DefaultWriter, func(c *gin.Context, err any) {
 		log.Println(err)
 		c.AbortWithStatusJSON(http.StatusInternalServerError, map[string]any{"code": 500, "msg": "系统错误，请重试!!!"})
 	}))
+
+	// 订阅消费等指标通过 /metrics 暴露
+	if config.Get().App.EnableMetrics {
+		r.Use(metrics.Metrics(r, metrics.WithIgnoreStatusCodes(http.StatusNotFound)))
+	}
+
 	r.NoRoute(func(c *gin.Context) {
 		c.JSON(http.StatusNotFound, map[string]any{"msg": "请求地址不存在"})
 	})
 
-	messageRouter(r, handler.NewMessageHandler())
+	messageRouter(r, h)
 
 	return r
 }
 
+// adminAuth 校验管理接口令牌，未配置令牌时拒绝全部请求
+func adminAuth(token string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if token == "" {
+			c.AbortWithStatusJSON(http.StatusForbidden, map[string]any{"msg": "管理接口未启用"})
+			return
+		}
+
+		bearer := strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if subtle.ConstantTimeCompare([]byte(bearer), []byte(token)) != 1 {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, map[string]any{"msg": "管理令牌无效"})
+			return
+		}
+
+		c.Next()
+	}
+}
+
 func messageRouter(group *gin.Engine, h handler.MessageHandler) {
 	routerGroup := group.Group("ws")
-	routerGroup.GET("/connect/detail", func(ctx *gin.Context) {
-		ctx.JSON(200, map[string]any{
-			"chat": socket.Session.Chat.Count(),
-			//"example": socket.Session.Example.Count(),
-		})
-	})
+	routerGroup.GET("/connect/detail", h.Detail)
+
+	// 集群节点、用户连接查询及强制断开，需携带 socket.adminToken
+	admin := adminAuth(config.Get().Socket.AdminToken)
+	routerGroup.GET("/cluster/nodes", admin, h.ClusterNodes)
+	routerGroup.GET("/cluster/sessions", admin, h.ClusterSessions)
+	routerGroup.POST("/cluster/kick", admin, h.ClusterKick)
+
+	// 未送达消息数，供离线推送服务使用，同样需携带 socket.adminToken
+	routerGroup.GET("/ack/undelivered", admin, h.Undelivered)
+
+	// 各渠道连接地址 /ws/{channel}.io
+	routerGroup.GET("/:channel", verify.AuthWSMiddleware(), h.Connection)
 
-	routerGroup.GET("/chat.io", verify.AuthWSMiddleware(), h.Connection)
+	// 无法使用 WebSocket 时的降级传输，SSE 或长轮询接收消息，POST 发送消息
+	routerGroup.GET("/:channel/sse", verify.AuthWSMiddleware(), h.Sse)
+	routerGroup.GET("/:channel/poll", verify.AuthWSMiddleware(), h.Poll)
+	routerGroup.POST("/:channel/send", verify.AuthWSMiddleware(), h.Send)
 }
@@ -1,10 +1,29 @@
 package types
 
-import "lingua_exchange/internal/constant"
+import (
+	"encoding/json"
 
+	"lingua_exchange/internal/constant"
+)
+
+// SubscribeContentVersion 当前订阅消息结构版本，新增不兼容字段时递增
+const SubscribeContentVersion = 1
+
+// SubscribeContent 消息总线信封
 type SubscribeContent struct {
-	Event string `json:"event"`
-	Data  string `json:"data"`
+	Version int    `json:"version,omitempty"` // 结构版本，旧节点发布的消息为 0
+	Event   string `json:"event"`             // 订阅事件名
+	Data    string `json:"data"`              // 事件内容
+}
+
+// TcpAuthorize TCP 连接首帧授权信息
+type TcpAuthorize struct {
+	Token   string `json:"token"`
+	Channel string `json:"channel"`
+	Codec   string `json:"codec"` // 后续消息的编码格式，默认 json
+
+	Platform string `json:"platform"` // 客户端平台
+	Device   string `json:"device"`   // 设备标识
 }
 
 type RoomOption struct {
@@ -37,6 +56,42 @@ type ConsumeTalkKeyboard struct {
 	ReceiverID int `json:"receiver_id"`
 }
 
+// ConsumeClientKick 强制断开用户连接，Platform 及 Device 为空时断开全部连接
+type ConsumeClientKick struct {
+	UserID   int    `json:"user_id"`
+	Platform string `json:"platform"`
+	Device   string `json:"device"`
+	Reason   string `json:"reason"`
+}
+
+// ConsumeSessionReplaced 同平台新连接替换节点上的旧连接，Platform 及 Device 为新连接的信息
+type ConsumeSessionReplaced struct {
+	UserID   int     `json:"user_id"`
+	Channel  string  `json:"channel"`
+	Cids     []int64 `json:"cids"`
+	Platform string  `json:"platform"`
+	Device   string  `json:"device"`
+}
+
+// ImSessionReplaced 连接被同平台新登录替换通知
+type ImSessionReplaced struct {
+	Platform string `json:"platform"`
+	Device   string `json:"device"`
+}
+
+// ClientKickRequest 强制断开用户连接请求，Platform 及 Device 为空时断开全部连接
+type ClientKickRequest struct {
+	Uid      int    `json:"uid" binding:"required"`
+	Platform string `json:"platform"`
+	Device   string `json:"device"`
+	Reason   string `json:"reason"`
+}
+
+// ImKicked 连接被强制断开通知
+type ImKicked struct {
+	Reason string `json:"reason"`
+}
+
 type ConsumeGroupJoin struct {
 	Gid  int   `json:"group_id"`
 	Type int   `json:"type"`
@@ -48,7 +103,77 @@ type ConsumeGroupApply struct {
 	UserId  int `json:"user_id"`
 }
 
+// ConsumeChannelPush 自定义渠道推送消息
+type ConsumeChannelPush struct {
+	Uids      []int           `json:"uids"`      // 接收用户ID
+	Broadcast bool            `json:"broadcast"` // 是否推送给渠道内所有客户端
+	Ack       bool            `json:"ack"`       // 是否需要客户端确认
+	Event     string          `json:"event"`     // 推送事件名
+	Content   json.RawMessage `json:"content"`   // 推送内容
+}
+
 type ConsumeContactStatus struct {
 	Status int `json:"status"`
 	UserId int `json:"user_id"`
 }
+
+// EventTalkKeyboard 客户端键盘输入事件
+type EventTalkKeyboard struct {
+	ReceiverId int `json:"receiver_id" binding:"required,gt=0"`
+}
+
+// EventTalkRead 客户端消息已读事件
+type EventTalkRead struct {
+	ReceiverId int      `json:"receiver_id" binding:"required,gt=0"`
+	MsgIds     []string `json:"msg_ids" binding:"required,min=1"`
+}
+
+// EventTalkRevoke 客户端撤回消息事件
+type EventTalkRevoke struct {
+	MsgId string `json:"msg_id" binding:"required"`
+}
+
+// EventTokenRefresh 客户端续期连接令牌事件
+type EventTokenRefresh struct {
+	Token string `json:"token" binding:"required"`
+}
+
+// EventSyncResume 客户端断线重连后的消息同步事件，会话游标与全局游标至少提供一项
+type EventSyncResume struct {
+	Sessions []*SyncResumeSession `json:"sessions" binding:"omitempty,max=100,dive"` // 各会话最后收到的消息时序
+	SyncTime int64                `json:"sync_time" binding:"min=0"`                 // 全局同步游标(秒级时间戳)
+}
+
+// SyncResumeSession 会话同步游标
+type SyncResumeSession struct {
+	TalkType   int `json:"talk_type" binding:"required,oneof=1 2"`
+	ReceiverId int `json:"receiver_id" binding:"required,gt=0"`
+	Sequence   int `json:"sequence" binding:"min=0"`
+}
+
+// SyncResumed 消息同步完成通知内容
+type SyncResumed struct {
+	Sessions []*SyncResumedSession `json:"sessions"`
+}
+
+// SyncResumedSession 会话同步结果
+type SyncResumedSession struct {
+	TalkType   int  `json:"talk_type"`
+	ReceiverId int  `json:"receiver_id"`
+	Sequence   int  `json:"sequence"` // 已同步到的消息时序
+	Count      int  `json:"count"`    // 本次补发的消息数
+	HasMore    bool `json:"has_more"` // 超出补发上限，剩余消息需通过历史记录接口拉取
+}
+
+// TokenExpired 连接令牌过期通知内容
+type TokenExpired struct {
+	Grace int `json:"grace"` // 宽限时间(秒)，超时未续期将断开连接
+}
+
+// EventReply 客户端事件处理回执
+type EventReply struct {
+	Event string `json:"event"`          // 客户端上行事件名
+	Code  int    `json:"code"`           // 错误码，0 表示成功
+	Msg   string `json:"msg"`            // 错误描述
+	Data  any    `json:"data,omitempty"` // 返回数据
+}
@@ -162,4 +162,6 @@ type FindAllTalkRecordsOpt struct {
 	MsgType    []int // 消息类型
 	Cursor     int   // 上次查询的游标
 	Limit      int   // 数据行数
+	Forward    bool  // 是否查询游标之后的消息(按时序升序)
+	Since      int64 // 仅查询该时间(秒级时间戳)之后的消息
 }
@@ -3,8 +3,8 @@ package jwt
 import (
 	"context"
 	"errors"
-	"net/url"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -23,7 +23,11 @@ var (
 
 	// cache prefix key, must end with a colon
 	tokenCachePrefixKey = "access_token:"
+	tokenUserIndexKey   = "user_access_tokens:" // 用户签发的令牌集合，用于注销全部令牌
 	tokenUserKey        = "user_id:"
+	tokenValueKey       = "access_token"
+	wsProtocolKey       = "Sec-WebSocket-Protocol"
+	wsProtocolToken     = "access_token"
 	authorizationKey    = "Authorization"
 	refreshTokenKey     = "Refresh-Token"
 	env                 = "env"
@@ -31,6 +35,15 @@ var (
 	deviceToken         = "deviceToken"
 )
 
+// Init 初始化令牌签名配置，签发与校验令牌前调用
+func Init() {
+	jwt.Init(
+		jwt.WithExpire(UserTokenExpireTime),
+		jwt.WithSigningKey("live_lingua:"),
+		jwt.WithSigningMethod(jwt.HS384),
+	)
+}
+
 func GenerateTokens(userID uint64) (string, string, error) {
 	accessToken, accessExp, err := createToken(userID, UserTokenExpireTime)
 	if err != nil {
@@ -60,7 +73,32 @@ func createToken(userID uint64, duration time.Duration) (string, int64, error) {
 // 存储Access Token到Redis
 func storeAccessTokenInRedis(token string, userID uint64, exp int64) error {
 	ctx := context.Background()
-	return model.GetRedisCli().Set(ctx, tokenCachePrefixKey+token, userID, time.Until(time.Unix(exp, 0))).Err()
+	index := tokenUserIndexKey + utils.Uint64ToStr(userID)
+
+	_, err := model.GetRedisCli().Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Set(ctx, tokenCachePrefixKey+token, userID, time.Until(time.Unix(exp, 0)))
+		pipe.SAdd(ctx, index, token)
+		pipe.Expire(ctx, index, UserTokenExpireTime)
+		return nil
+	})
+	return err
+}
+
+// RevokeUserTokens 注销用户已签发的全部Access Token
+func RevokeUserTokens(ctx context.Context, uid int) error {
+	index := tokenUserIndexKey + strconv.Itoa(uid)
+
+	tokens, err := model.GetRedisCli().SMembers(ctx, index).Result()
+	if err != nil {
+		return err
+	}
+
+	keys := []string{index}
+	for _, token := range tokens {
+		keys = append(keys, tokenCachePrefixKey+token)
+	}
+
+	return model.GetRedisCli().Del(ctx, keys...).Err()
 }
 
 // ValidateAndRefreshTokens Token验证并无感刷新
@@ -110,41 +148,68 @@ func ValidateAndRefreshTokens(c *gin.Context) {
 }
 
 func ValidateWSToken(c *gin.Context) {
-	wsURL := c.Request.URL.String()
-
-	// 解析 URL
-	parsedURL, err := url.Parse(wsURL)
-	if err != nil {
-		c.AbortWithStatusJSON(400, gin.H{"error": "Invalid URL"})
+	authorization, protocol := wsRequestToken(c)
+	if authorization == "" {
+		c.AbortWithStatusJSON(401, gin.H{"error": "authorizationKey token required"})
 		return
 	}
-	queryParams := parsedURL.Query()
-	authorization := queryParams.Get(authorizationKey)
 
 	uid, err := validateToken(authorization, c)
 	if err != nil {
-		c.JSON(401, gin.H{"error": "refresh token expired, please re-login"})
-		c.Abort()
+		c.AbortWithStatusJSON(401, gin.H{"error": "access token expired, please re-login"})
 		return
 	}
+
+	// 通过子协议携带令牌时需回写协议名，否则浏览器会拒绝握手
+	if protocol != "" {
+		c.Header(wsProtocolKey, protocol)
+	}
+
 	// 将授权令牌放入上下文
 	c.Set(tokenUserKey, uid)
+	c.Set(tokenValueKey, authorization)
 
 	// 调用下一个中间件/处理程序
 	c.Next()
 
 }
 
+// wsRequestToken 依次从 Sec-WebSocket-Protocol、Authorization 请求头及 URL 参数中读取令牌
+// 子协议格式为 "access_token, <token>"，返回值 protocol 为需要回写的子协议名
+func wsRequestToken(c *gin.Context) (token string, protocol string) {
+	protocols := strings.Split(c.GetHeader(wsProtocolKey), ",")
+	if len(protocols) == 2 && strings.TrimSpace(protocols[0]) == wsProtocolToken {
+		if token = strings.TrimSpace(protocols[1]); token != "" {
+			return token, wsProtocolToken
+		}
+	}
+
+	if token = strings.TrimSpace(strings.TrimPrefix(c.GetHeader(authorizationKey), "Bearer ")); token != "" {
+		return token, ""
+	}
+
+	return c.Query(authorizationKey), ""
+}
+
 // 验证Token有效性
 func validateToken(tokenString string, c *gin.Context) (string, error) {
+	uid, err := VerifyToken(context.Background(), tokenString)
+	if err != nil {
+		logger.Warn("VerifyToken error: ", logger.Err(err), middleware.GCtxRequestIDField(c))
+		return "", err
+	}
+
+	return uid, nil
+}
+
+// VerifyToken 校验Token签名及Redis中的有效状态，返回用户ID，供非HTTP连接复用
+func VerifyToken(ctx context.Context, tokenString string) (string, error) {
 	token, err := jwt.ParseToken(tokenString)
 	if err != nil {
-		logger.Warn("ParseToken error: ", logger.Err(err), middleware.GCtxRequestIDField(c))
 		return "", err
 	}
 
 	// 检查Redis中Token是否有效
-	ctx := context.Background()
 	_, err = model.GetRedisCli().Get(ctx, tokenCachePrefixKey+tokenString).Result()
 	if errors.Is(err, redis.Nil) {
 		return "", errors.New("token expired")
@@ -155,6 +220,35 @@ func validateToken(tokenString string, c *gin.Context) (string, error) {
 	return token.UID, nil
 }
 
+// TokenExpireAt 获取Token失效时间，取签名有效期与Redis缓存有效期中较早者
+func TokenExpireAt(ctx context.Context, tokenString string) (time.Time, error) {
+	token, err := jwt.ParseToken(tokenString)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	expireAt := time.Now().Add(UserTokenExpireTime)
+	if token.ExpiresAt != nil {
+		expireAt = token.ExpiresAt.Time
+	}
+
+	ttl, err := model.GetRedisCli().TTL(ctx, tokenCachePrefixKey+tokenString).Result()
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	// -2 表示Token已被删除，-1 表示未设置过期时间
+	if ttl == -2 {
+		return time.Time{}, errors.New("token expired")
+	}
+
+	if ttl > 0 && time.Now().Add(ttl).Before(expireAt) {
+		expireAt = time.Now().Add(ttl)
+	}
+
+	return expireAt, nil
+}
+
 // AuthMiddleware 自动Token验证和无感刷新中间件 开发环境
 func AuthMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
@@ -168,19 +262,18 @@ func AuthWSMiddleware() gin.HandlerFunc {
 	}
 }
 
-func ParseWSUrlUserId(c *gin.Context) string {
-	wsURL := c.Request.URL.String()
-
-	// 解析 URL
-	parsedURL, err := url.Parse(wsURL)
-	if err != nil {
-		c.AbortWithStatusJSON(400, gin.H{"error": "Invalid URL"})
-		return ""
+// WSUserId 获取 WebSocket 握手时令牌中的用户ID，需配合 AuthWSMiddleware 使用
+func WSUserId(c *gin.Context) (int, error) {
+	uid := c.GetString(tokenUserKey)
+	if uid == "" {
+		return 0, errors.New("unauthorized websocket connection")
 	}
-	queryParams := parsedURL.Query()
-	userId := queryParams.Get(tokenUserKey)
-	return userId
+	return convertUID(uid)
+}
 
+// WSToken 获取 WebSocket 握手时使用的令牌
+func WSToken(c *gin.Context) string {
+	return c.GetString(tokenValueKey)
 }
 
 func HeaderDevMode(c *gin.Context) (string, error) {
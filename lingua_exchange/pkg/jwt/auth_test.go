@@ -0,0 +1,34 @@
+package jwt
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/zhufuyi/sponge/pkg/jwt"
+)
+
+func TestInit(t *testing.T) {
+	Init()
+
+	token, _, err := createToken(7, UserTokenExpireTime)
+	assert.NoError(t, err)
+
+	header, err := base64.RawURLEncoding.DecodeString(strings.Split(token, ".")[0])
+	assert.NoError(t, err)
+	assert.Contains(t, string(header), `"alg":"HS384"`)
+
+	claims, err := jwt.ParseToken(token)
+	assert.NoError(t, err)
+	assert.Equal(t, "7", claims.UID)
+
+	// 其他密钥签发的令牌校验失败
+	jwt.Init(jwt.WithSigningKey("other"), jwt.WithSigningMethod(jwt.HS384))
+	other, err := jwt.GenerateToken("7")
+	assert.NoError(t, err)
+
+	Init()
+	_, err = jwt.ParseToken(other)
+	assert.Error(t, err)
+}
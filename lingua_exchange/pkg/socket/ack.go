@@ -2,15 +2,39 @@ package socket
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"log"
 	"time"
 
+	"github.com/zhufuyi/sponge/pkg/logger"
 	"lingua_exchange/pkg/timewheel"
 )
 
 var ack *AckBuffer
 
+// IAckStorage 未确认消息的持久化存储，按用户、渠道及设备保存，断线后可在任意节点重投
+type IAckStorage interface {
+	Save(ctx context.Context, scope *AckScope, sid string, data []byte) error
+	Delete(ctx context.Context, scope *AckScope, sid string) error
+	// All 按发送顺序返回未确认消息
+	All(ctx context.Context, scope *AckScope) ([]*AckMessage, error)
+}
+
+// AckScope 未确认消息的归属，每条推送的 ACK ID 只属于一个连接，
+// 重连后只重投同一设备在同一渠道收到的消息，未上报设备标识的连接共用一份
+type AckScope struct {
+	Uid     int    // 用户ID
+	Channel string // 渠道名称
+	Device  string // 设备标识
+}
+
+// AckMessage 持久化的未确认消息
+type AckMessage struct {
+	Sid  string // ACK ID
+	Data []byte // 推送内容
+}
+
 // AckBuffer Ack 确认缓冲区
 type AckBuffer struct {
 	timeWheel *timewheel.SimpleTimeWheel[*AckBufferContent]
@@ -39,12 +63,77 @@ func (a *AckBuffer) Start(ctx context.Context) error {
 	return errors.New("ack service stopped")
 }
 
-func (a *AckBuffer) insert(ackKey string, value *AckBufferContent) {
-	a.timeWheel.Add(ackKey, value, time.Duration(5)*time.Second)
+// insert 记录已发送待确认的消息，首次发送时持久化，按指数退避重试
+func (a *AckBuffer) insert(c *Client, data *ClientResponse) {
+	data.attempts++
+
+	if !data.stored && c.ackStorage != nil {
+		if err := c.ackStorage.Save(context.TODO(), c.ackScope(), data.Sid, marshalAck(c, data)); err != nil {
+			logger.Error("[ERROR] save ack err: ", logger.Err(err))
+		}
+
+		data.stored = true
+	}
+
+	// 超过重试次数后仅保留持久化记录，等待下次连接重投
+	if data.attempts > data.Retry {
+		return
+	}
+
+	a.timeWheel.Add(data.Sid, &AckBufferContent{
+		cid:      c.cid,
+		uid:      int64(c.uid),
+		channel:  c.channel.Name(),
+		response: data,
+	}, c.channel.Options().ackDelay(data.attempts))
 }
 
-func (a *AckBuffer) delete(ackKey string) {
+// delete 客户端确认消息
+func (a *AckBuffer) delete(c *Client, ackKey string) {
 	a.timeWheel.Remove(ackKey)
+
+	if c.ackStorage != nil {
+		if err := c.ackStorage.Delete(context.TODO(), c.ackScope(), ackKey); err != nil {
+			logger.Error("[ERROR] delete ack err: ", logger.Err(err))
+		}
+	}
+}
+
+// redeliver 重投用户未确认的消息
+func (a *AckBuffer) redeliver(c *Client) {
+	if c.ackStorage == nil {
+		return
+	}
+
+	scope := c.ackScope()
+
+	items, err := c.ackStorage.All(context.TODO(), scope)
+	if err != nil {
+		logger.Error("[ERROR] load ack err: ", logger.Err(err))
+		return
+	}
+
+	// 重投的消息先于实时推送发送
+	err = c.Resume(func(write func(data *ClientResponse) error) error {
+		for _, item := range items {
+			data := &ClientResponse{}
+			if err := json.Unmarshal(item.Data, data); err != nil {
+				_ = c.ackStorage.Delete(context.TODO(), scope, item.Sid)
+				continue
+			}
+
+			data.IsAck, data.Sid, data.Retry, data.stored = true, item.Sid, c.channel.Options().AckRetry, true
+
+			if err := write(data); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		logger.Error("[ERROR] redeliver ack err: ", logger.Err(err))
+	}
 }
 
 func (a *AckBuffer) handle(_ *timewheel.SimpleTimeWheel[*AckBufferContent], _ string, bufferContent *AckBufferContent) {
@@ -67,3 +156,24 @@ func (a *AckBuffer) handle(_ *timewheel.SimpleTimeWheel[*AckBufferContent], _ st
 		log.Println("ack err: ", err)
 	}
 }
+
+// ackScope 客户端未确认消息的归属
+func (c *Client) ackScope() *AckScope {
+	scope := &AckScope{Uid: c.uid, Channel: c.channel.Name()}
+	if c.meta != nil {
+		scope.Device = c.meta.Device
+	}
+
+	return scope
+}
+
+// marshalAck 持久化的消息统一使用 JSON，与客户端编码格式无关
+func marshalAck(c *Client, data *ClientResponse) []byte {
+	if c.codec != nil && c.codec.Name() != CodecJSON {
+		bt, _ := json.Marshal(data)
+		return bt
+	}
+
+	bt, _ := data.encode()
+	return bt
+}
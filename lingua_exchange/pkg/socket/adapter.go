@@ -1,5 +1,7 @@
 package socket
 
+import "time"
+
 type IConn interface {
 	// Read 数据读取
 	Read() ([]byte, error)
@@ -9,6 +11,8 @@ type IConn interface {
 	Close() error
 	// SetCloseHandler 设置连接关闭回调事件
 	SetCloseHandler(fn func(code int, text string) error)
+	// SetWriteDeadline 设置写超时时间
+	SetWriteDeadline(t time.Time) error
 	// Network 网络协议类型
 	Network() string
 }
@@ -2,6 +2,8 @@ package adapter
 
 // 适配器类型定义
 const (
-	NetworkWss = "wss"
-	NetworkTcp = "tcp"
+	NetworkWss  = "wss"
+	NetworkTcp  = "tcp"
+	NetworkSse  = "sse"
+	NetworkPoll = "poll"
 )
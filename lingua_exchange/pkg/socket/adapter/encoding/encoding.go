@@ -37,7 +37,8 @@ func NewEncode(data []byte) ([]byte, error) {
 		return nil, err
 	}
 
-	buffer := buf.Bytes()
+	// 缓冲区放回池中后会被复用，需拷贝一份返回
+	buffer := bytes.Clone(buf.Bytes())
 	buf.Reset()
 	bufferPool.Put(buf)
 
@@ -0,0 +1,197 @@
+package adapter
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// HTTP 降级传输：SSE/长轮询下行，POST 上行，用于无法建立 WebSocket 的网络环境
+
+const httpBuffer = 64 // 上下行缓冲消息数
+
+var (
+	ErrHttpClosed     = errors.New("http connection has been closed")
+	errHttpBufferFull = httpTimeoutError{}
+)
+
+// httpTimeoutError 下行缓冲区写入超时，实现 net.Error 以触发慢客户端处理
+type httpTimeoutError struct{}
+
+func (httpTimeoutError) Error() string   { return "http connection write timeout" }
+func (httpTimeoutError) Timeout() bool   { return true }
+func (httpTimeoutError) Temporary() bool { return true }
+
+// httpSessions 本节点的 HTTP 连接，按会话ID索引
+var httpSessions sync.Map
+
+// HttpAdapter HTTP 降级传输适配器，一个会话对应一个客户端连接
+type HttpAdapter struct {
+	id       string
+	uid      int
+	network  string
+	inbound  chan []byte
+	outbound chan []byte
+	done     chan struct{}
+	once     sync.Once
+
+	mu       sync.Mutex
+	deadline time.Time
+}
+
+// NewHttpAdapter 创建 HTTP 连接，network 为 NetworkSse 或 NetworkPoll，uid 为会话所属用户
+func NewHttpAdapter(network string, uid int) *HttpAdapter {
+	conn := &HttpAdapter{
+		id:       strings.ReplaceAll(uuid.New().String(), "-", ""),
+		uid:      uid,
+		network:  network,
+		inbound:  make(chan []byte, httpBuffer),
+		outbound: make(chan []byte, httpBuffer),
+		done:     make(chan struct{}),
+	}
+
+	httpSessions.Store(conn.id, conn)
+
+	return conn
+}
+
+// HttpSession 获取用户的 HTTP 连接
+func HttpSession(id string, uid int) (*HttpAdapter, bool) {
+	val, ok := httpSessions.Load(id)
+	if !ok {
+		return nil, false
+	}
+
+	conn := val.(*HttpAdapter)
+	if conn.uid != uid {
+		return nil, false
+	}
+
+	return conn, true
+}
+
+// Id 会话ID
+func (h *HttpAdapter) Id() string {
+	return h.id
+}
+
+// Deliver 投递客户端上行消息（POST）
+func (h *HttpAdapter) Deliver(ctx context.Context, data []byte) error {
+	select {
+	case <-h.done:
+		return ErrHttpClosed
+	default:
+	}
+
+	select {
+	case h.inbound <- data:
+		return nil
+	case <-h.done:
+		return ErrHttpClosed
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Next 等待下一条下行消息（SSE）
+func (h *HttpAdapter) Next(ctx context.Context) ([]byte, error) {
+	select {
+	case data := <-h.outbound:
+		return data, nil
+	case <-h.done:
+		return nil, ErrHttpClosed
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// Poll 等待下行消息，最多等待 wait，返回期间积压的所有消息（长轮询）
+func (h *HttpAdapter) Poll(ctx context.Context, wait time.Duration) ([][]byte, error) {
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+
+	items := make([][]byte, 0)
+
+	select {
+	case data := <-h.outbound:
+		items = append(items, data)
+	case <-h.done:
+		return nil, ErrHttpClosed
+	case <-ctx.Done():
+		return items, nil
+	case <-timer.C:
+		return items, nil
+	}
+
+	for {
+		select {
+		case data := <-h.outbound:
+			items = append(items, data)
+		default:
+			return items, nil
+		}
+	}
+}
+
+func (h *HttpAdapter) Network() string {
+	return h.network
+}
+
+func (h *HttpAdapter) Read() ([]byte, error) {
+	select {
+	case data := <-h.inbound:
+		return data, nil
+	case <-h.done:
+		return nil, ErrHttpClosed
+	}
+}
+
+func (h *HttpAdapter) Write(bytes []byte) error {
+	h.mu.Lock()
+	deadline := h.deadline
+	h.mu.Unlock()
+
+	var timeout <-chan time.Time
+	if !deadline.IsZero() {
+		timer := time.NewTimer(time.Until(deadline))
+		defer timer.Stop()
+		timeout = timer.C
+	}
+
+	select {
+	case h.outbound <- bytes:
+		return nil
+	case <-h.done:
+		return ErrHttpClosed
+	case <-timeout:
+		return errHttpBufferFull
+	}
+}
+
+func (h *HttpAdapter) SetWriteDeadline(t time.Time) error {
+	h.mu.Lock()
+	h.deadline = t
+	h.mu.Unlock()
+	return nil
+}
+
+func (h *HttpAdapter) Close() error {
+	h.once.Do(func() {
+		httpSessions.Delete(h.id)
+		close(h.done)
+	})
+
+	return nil
+}
+
+// SetCloseHandler 连接关闭由 Read 返回错误触发，无需回调
+func (h *HttpAdapter) SetCloseHandler(_ func(code int, text string) error) {}
+
+// Done 连接关闭通知
+func (h *HttpAdapter) Done() <-chan struct{} {
+	return h.done
+}
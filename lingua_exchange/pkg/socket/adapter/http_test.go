@@ -0,0 +1,58 @@
+package adapter
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHttpAdapter(t *testing.T) {
+	conn := NewHttpAdapter(NetworkPoll, 1)
+
+	_, ok := HttpSession(conn.Id(), 2)
+	assert.False(t, ok)
+
+	found, ok := HttpSession(conn.Id(), 1)
+	assert.True(t, ok)
+	assert.Equal(t, conn, found)
+
+	ctx := context.Background()
+
+	// 上行消息
+	assert.NoError(t, conn.Deliver(ctx, []byte(`{"event":"ping"}`)))
+	data, err := conn.Read()
+	assert.NoError(t, err)
+	assert.Equal(t, `{"event":"ping"}`, string(data))
+
+	// 下行消息一次轮询全部取出
+	assert.NoError(t, conn.Write([]byte("a")))
+	assert.NoError(t, conn.Write([]byte("b")))
+	items, err := conn.Poll(ctx, time.Second)
+	assert.NoError(t, err)
+	assert.Equal(t, [][]byte{[]byte("a"), []byte("b")}, items)
+
+	items, err = conn.Poll(ctx, 10*time.Millisecond)
+	assert.NoError(t, err)
+	assert.Empty(t, items)
+
+	// 缓冲区写满后按写超时返回
+	for i := 0; i < cap(conn.outbound); i++ {
+		assert.NoError(t, conn.Write([]byte("x")))
+	}
+	_ = conn.SetWriteDeadline(time.Now().Add(10 * time.Millisecond))
+	err = conn.Write([]byte("y"))
+	var netErr net.Error
+	assert.ErrorAs(t, err, &netErr)
+	assert.True(t, netErr.Timeout())
+
+	assert.NoError(t, conn.Close())
+	_, ok = HttpSession(conn.Id(), 1)
+	assert.False(t, ok)
+
+	_, err = conn.Read()
+	assert.ErrorIs(t, err, ErrHttpClosed)
+	assert.ErrorIs(t, conn.Deliver(ctx, []byte("z")), ErrHttpClosed)
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"io"
 	"net"
+	"time"
 
 	"lingua_exchange/pkg/socket/adapter/encoding"
 )
@@ -57,6 +58,10 @@ func (t *TcpAdapter) Write(bytes []byte) error {
 	return err
 }
 
+func (t *TcpAdapter) SetWriteDeadline(deadline time.Time) error {
+	return t.conn.SetWriteDeadline(deadline)
+}
+
 func (t *TcpAdapter) Close() error {
 	return t.conn.Close()
 }
@@ -1,32 +1,180 @@
 package adapter
 
 import (
+	"encoding/json"
+	"io"
 	"net/http"
+	"net/url"
+	"strings"
+	"time"
 
 	"github.com/gorilla/websocket"
 )
 
-// WsAdapter Websocket 适配器
-type WsAdapter struct {
-	conn *websocket.Conn
+// WsOptions WebSocket 升级配置
+type WsOptions struct {
+	ReadBufferSize       int      // 读缓冲区大小，默认 4KB
+	WriteBufferSize      int      // 写缓冲区大小，默认 4KB
+	ReadLimit            int64    // 单条消息最大字节数，超出后以 1009 关闭连接，默认 64KB
+	Compression          bool     // 是否启用 permessage-deflate 压缩
+	CompressionLevel     int      // 压缩级别 1~9，默认 1
+	CompressionThreshold int      // 不小于该字节数的消息才压缩，默认 1KB
+	AllowedOrigins       []string // 允许的 Origin，支持 * 及 https://*.example.com，为空时仅允许同源或不带 Origin 的请求
 }
 
-var defaultUpGrader = websocket.Upgrader{
-	ReadBufferSize:  1024,
-	WriteBufferSize: 1024,
-	CheckOrigin: func(r *http.Request) bool {
-		return true
-	},
+// withDefaults 未设置的配置项使用默认值
+func (o *WsOptions) withDefaults() *WsOptions {
+	opts := WsOptions{}
+	if o != nil {
+		opts = *o
+	}
+
+	if opts.ReadBufferSize <= 0 {
+		opts.ReadBufferSize = 4 << 10
+	}
+
+	if opts.WriteBufferSize <= 0 {
+		opts.WriteBufferSize = 4 << 10
+	}
+
+	if opts.ReadLimit <= 0 {
+		opts.ReadLimit = 64 << 10
+	}
+
+	if opts.CompressionLevel < 1 || opts.CompressionLevel > 9 {
+		opts.CompressionLevel = 1
+	}
+
+	if opts.CompressionThreshold <= 0 {
+		opts.CompressionThreshold = 1 << 10
+	}
+
+	return &opts
 }
 
-func NewWsAdapter(w http.ResponseWriter, r *http.Request) (*WsAdapter, error) {
+// WsUpgrader WebSocket 连接升级
+type WsUpgrader struct {
+	upgrader websocket.Upgrader
+	options  *WsOptions
+}
+
+var defaultUpgrader = NewWsUpgrader(nil)
 
-	conn, err := defaultUpGrader.Upgrade(w, r, w.Header())
+// NewWsUpgrader 创建连接升级器，options 为空时使用默认配置
+func NewWsUpgrader(options *WsOptions) *WsUpgrader {
+	options = options.withDefaults()
+
+	return &WsUpgrader{
+		options: options,
+		upgrader: websocket.Upgrader{
+			ReadBufferSize:    options.ReadBufferSize,
+			WriteBufferSize:   options.WriteBufferSize,
+			EnableCompression: options.Compression,
+			CheckOrigin:       checkOrigin(options.AllowedOrigins),
+			Error:             upgradeError,
+		},
+	}
+}
+
+// Upgrade 升级为 WebSocket 连接，失败时已回写对应的 HTTP 状态码
+func (u *WsUpgrader) Upgrade(w http.ResponseWriter, r *http.Request) (*WsAdapter, error) {
+	conn, err := u.upgrader.Upgrade(w, r, w.Header())
 	if err != nil {
 		return nil, err
 	}
 
-	return &WsAdapter{conn: conn}, nil
+	conn.SetReadLimit(u.options.ReadLimit)
+	if u.options.Compression {
+		_ = conn.SetCompressionLevel(u.options.CompressionLevel)
+	}
+
+	return &WsAdapter{
+		conn:        conn,
+		messageType: websocket.TextMessage,
+		compression: u.options.Compression,
+		threshold:   u.options.CompressionThreshold,
+		readLimit:   u.options.ReadLimit,
+	}, nil
+}
+
+// ReadLimit 单条消息最大字节数
+func (u *WsUpgrader) ReadLimit() int64 {
+	return u.options.ReadLimit
+}
+
+// checkOrigin 校验请求来源
+func checkOrigin(allowed []string) func(r *http.Request) bool {
+	if len(allowed) == 0 {
+		return nil // 使用默认的同源校验
+	}
+
+	return func(r *http.Request) bool {
+		origin := r.Header.Get("Origin")
+		if origin == "" {
+			return true // 非浏览器客户端
+		}
+
+		u, err := url.Parse(origin)
+		if err != nil || u.Host == "" {
+			return false
+		}
+
+		for _, item := range allowed {
+			if matchOrigin(u, item) {
+				return true
+			}
+		}
+
+		return false
+	}
+}
+
+// matchOrigin 判断来源是否匹配允许的 Origin
+func matchOrigin(origin *url.URL, allowed string) bool {
+	if allowed == "*" {
+		return true
+	}
+
+	scheme, host, ok := strings.Cut(allowed, "://")
+	if !ok || !strings.EqualFold(scheme, origin.Scheme) {
+		return false
+	}
+
+	if suffix, ok := strings.CutPrefix(host, "*."); ok {
+		return strings.HasSuffix(strings.ToLower(origin.Host), "."+strings.ToLower(suffix))
+	}
+
+	return strings.EqualFold(host, origin.Host)
+}
+
+// upgradeError 升级失败时返回 JSON 错误信息
+func upgradeError(w http.ResponseWriter, _ *http.Request, status int, reason error) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]string{"error": reason.Error()})
+}
+
+// WsAdapter Websocket 适配器
+type WsAdapter struct {
+	conn        *websocket.Conn
+	messageType int   // 写入的帧类型
+	compression bool  // 是否启用压缩
+	threshold   int   // 压缩阈值
+	readLimit   int64 // 解压后的消息最大字节数
+}
+
+// NewWsAdapter 使用默认配置升级连接
+func NewWsAdapter(w http.ResponseWriter, r *http.Request) (*WsAdapter, error) {
+	return defaultUpgrader.Upgrade(w, r)
+}
+
+// SetBinary 设置是否以二进制帧写入
+func (w *WsAdapter) SetBinary(binary bool) {
+	if binary {
+		w.messageType = websocket.BinaryMessage
+	} else {
+		w.messageType = websocket.TextMessage
+	}
 }
 
 func (w *WsAdapter) Network() string {
@@ -34,12 +182,36 @@ func (w *WsAdapter) Network() string {
 }
 
 func (w *WsAdapter) Read() ([]byte, error) {
-	_, content, err := w.conn.ReadMessage()
-	return content, err
+	_, reader, err := w.conn.NextReader()
+	if err != nil {
+		return nil, err
+	}
+
+	// 读取限制仅作用于压缩后的帧，解压后的消息需再次限制
+	content, err := io.ReadAll(io.LimitReader(reader, w.readLimit+1))
+	if err != nil {
+		return nil, err
+	}
+
+	if int64(len(content)) > w.readLimit {
+		_ = w.conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseMessageTooBig, ""), time.Now().Add(time.Second))
+		return nil, websocket.ErrReadLimit
+	}
+
+	return content, nil
 }
 
 func (w *WsAdapter) Write(bytes []byte) error {
-	return w.conn.WriteMessage(websocket.TextMessage, bytes)
+	// 小消息压缩收益低于开销，不压缩
+	if w.compression {
+		w.conn.EnableWriteCompression(len(bytes) >= w.threshold)
+	}
+
+	return w.conn.WriteMessage(w.messageType, bytes)
+}
+
+func (w *WsAdapter) SetWriteDeadline(t time.Time) error {
+	return w.conn.SetWriteDeadline(t)
 }
 
 func (w *WsAdapter) Close() error {
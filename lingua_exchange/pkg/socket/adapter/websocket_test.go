@@ -0,0 +1,77 @@
+package adapter
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWsUpgrader(t *testing.T) {
+	upgrader := NewWsUpgrader(&WsOptions{
+		ReadLimit:            64,
+		Compression:          true,
+		CompressionThreshold: 8,
+		AllowedOrigins:       []string{"https://app.example.com", "https://*.lingua.io"},
+	})
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		for {
+			data, err := conn.Read()
+			if err != nil {
+				return
+			}
+			_ = conn.Write(data)
+		}
+	}))
+	defer server.Close()
+
+	addr := "ws" + strings.TrimPrefix(server.URL, "http")
+	dial := func(origin string) (*websocket.Conn, *http.Response, error) {
+		header := http.Header{}
+		if origin != "" {
+			header.Set("Origin", origin)
+		}
+
+		dialer := websocket.Dialer{EnableCompression: true}
+		return dialer.Dial(addr, header)
+	}
+
+	for _, origin := range []string{"", "https://app.example.com", "https://m.lingua.io"} {
+		conn, _, err := dial(origin)
+		assert.NoError(t, err, origin)
+
+		assert.NoError(t, conn.WriteMessage(websocket.TextMessage, []byte("hello lingua")))
+		_, data, err := conn.ReadMessage()
+		assert.NoError(t, err)
+		assert.Equal(t, "hello lingua", string(data))
+
+		// 超过读取限制时连接被关闭
+		assert.NoError(t, conn.WriteMessage(websocket.TextMessage, []byte(strings.Repeat("x", 1024))))
+		_, _, err = conn.ReadMessage()
+		assert.True(t, websocket.IsCloseError(err, websocket.CloseMessageTooBig), err)
+
+		_ = conn.Close()
+	}
+
+	for _, origin := range []string{"https://evil.com", "http://app.example.com", "https://lingua.io.evil.com"} {
+		_, resp, err := dial(origin)
+		assert.Error(t, err, origin)
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode, origin)
+	}
+
+	// 非 WebSocket 请求
+	resp, err := http.Get(server.URL)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	_ = resp.Body.Close()
+}
@@ -0,0 +1,162 @@
+package socket
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sync/atomic"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/zhufuyi/sponge/pkg/logger"
+)
+
+// OverflowPolicy 客户端发送缓冲区已满时的处理策略
+// 默认 drop-newest 且缓冲区为 10，取代了原先缓冲区满时阻塞推送的行为，慢客户端不再拖慢渠道的消息分发；
+// 被丢弃的需确认消息会持久化，由下次连接重投
+type OverflowPolicy string
+
+const (
+	OverflowDropOldest OverflowPolicy = "drop-oldest" // 丢弃最早的待发送消息
+	OverflowDropNewest OverflowPolicy = "drop-newest" // 丢弃当前消息
+	OverflowDisconnect OverflowPolicy = "disconnect"  // 丢弃当前消息，累计溢出次数达到上限后断开连接
+)
+
+const (
+	CloseCodeSlowConsumer = 4008 // 客户端消费过慢被断开
+
+	defaultWriteTimeout = 10 * time.Second // 默认底层连接写超时
+	defaultMaxOverflow  = 10               // 默认断开前允许的溢出次数
+	maxPending          = 1000             // 回放期间暂存实时推送的最大数量
+)
+
+const (
+	overflowDroppedOldest = "dropped_oldest" // 丢弃最早的消息
+	overflowDroppedNewest = "dropped_newest" // 丢弃当前消息
+	overflowDisconnected  = "disconnected"   // 断开连接
+	overflowWriteTimeout  = "write_timeout"  // 底层连接写超时
+)
+
+var errClientOverflow = errors.New("client send buffer overflow")
+
+// overflowCounter 按渠道及处理结果统计慢客户端
+var overflowCounter = promauto.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "im",
+	Subsystem: "socket",
+	Name:      "client_overflow_total",
+	Help:      "Total number of slow consumer outcomes by channel and outcome.",
+}, []string{"channel", "outcome"})
+
+// overflow 发送缓冲区已满时按策略处理当前消息
+func (c *Client) overflow(data *ClientResponse) error {
+	switch c.overflowPolicy {
+	case OverflowDropOldest:
+		select {
+		case old := <-c.outChan:
+			c.drop(old)
+		default:
+		}
+
+		overflowCounter.WithLabelValues(c.channel.Name(), overflowDroppedOldest).Inc()
+
+		select {
+		case c.outChan <- data:
+			return nil
+		default:
+			c.drop(data)
+			return errClientOverflow
+		}
+	case OverflowDisconnect:
+		c.drop(data)
+		return c.overflowDisconnect()
+	default:
+		c.drop(data)
+		overflowCounter.WithLabelValues(c.channel.Name(), overflowDroppedNewest).Inc()
+		return errClientOverflow
+	}
+}
+
+// hold 回放期间暂存实时推送，超过暂存上限时按溢出策略处理，调用方需持有 c.mu
+func (c *Client) hold(data *ClientResponse) error {
+	if len(c.pending) < maxPending {
+		c.pending = append(c.pending, data)
+		return nil
+	}
+
+	switch c.overflowPolicy {
+	case OverflowDropOldest:
+		c.drop(c.pending[0])
+		c.pending = append(c.pending[1:], data)
+		overflowCounter.WithLabelValues(c.channel.Name(), overflowDroppedOldest).Inc()
+		return nil
+	case OverflowDisconnect:
+		c.drop(data)
+		return c.overflowDisconnect()
+	default:
+		c.drop(data)
+		overflowCounter.WithLabelValues(c.channel.Name(), overflowDroppedNewest).Inc()
+		return errClientOverflow
+	}
+}
+
+// drop 丢弃未发出的消息，需确认的消息持久化后由下次连接重投
+func (c *Client) drop(data *ClientResponse) {
+	if data == nil || !data.IsAck || data.stored || c.ackStorage == nil {
+		return
+	}
+
+	if err := c.ackStorage.Save(context.TODO(), c.ackScope(), data.Sid, marshalAck(c, data)); err != nil {
+		logger.Error("[ERROR] save dropped ack err: ", logger.Err(err))
+		return
+	}
+
+	data.stored = true
+}
+
+// overflowDisconnect 丢弃当前消息，累计溢出次数达到上限后断开连接
+func (c *Client) overflowDisconnect() error {
+	if atomic.AddInt32(&c.overflows, 1) < int32(c.maxOverflow) {
+		overflowCounter.WithLabelValues(c.channel.Name(), overflowDroppedNewest).Inc()
+		return errClientOverflow
+	}
+
+	if atomic.CompareAndSwapInt32(&c.overflows, int32(c.maxOverflow), -1) {
+		overflowCounter.WithLabelValues(c.channel.Name(), overflowDisconnected).Inc()
+
+		// 调用方可能持有客户端写锁，异步关闭
+		go c.Close(CloseCodeSlowConsumer, "客户端消费过慢，连接已关闭")
+	}
+
+	return errClientOverflow
+}
+
+// pushWait 阻塞写入发送通道，超过写超时时间返回错误
+func (c *Client) pushWait(data *ClientResponse) (err error) {
+	defer func() {
+		if e := recover(); e != nil {
+			err = fmt.Errorf("connection has been closed")
+		}
+	}()
+
+	if c.Closed() {
+		return fmt.Errorf("connection has been closed")
+	}
+
+	timer := time.NewTimer(c.writeTimeout)
+	defer timer.Stop()
+
+	select {
+	case c.outChan <- data:
+		return nil
+	case <-timer.C:
+		return errClientOverflow
+	}
+}
+
+// isTimeout 判断是否为网络超时错误
+func isTimeout(err error) bool {
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
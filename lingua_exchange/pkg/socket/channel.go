@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"log"
 	"strconv"
+	"sync"
 	"sync/atomic"
 	"time"
 
@@ -15,7 +16,9 @@ import (
 type IChannel interface {
 	Name() string
 	Count() int64
+	Options() *ChannelOptions
 	Client(cid int64) (*Client, bool)
+	ClientIds(uid int) []int64
 	Write(data *SenderContent)
 	addClient(client *Client)
 	delClient(client *Client)
@@ -27,10 +30,21 @@ type Channel struct {
 	count   int64                               // 客户端连接数
 	node    cmap.ConcurrentMap[string, *Client] // 客户端列表
 	outChan chan *SenderContent                 // 消息发送通道
+	options *ChannelOptions                     // 心跳及 ACK 配置
+
+	mu    sync.RWMutex               // 保护 users
+	users map[int]map[int64]struct{} // 用户ID关联的本节点客户端ID
 }
 
-func NewChannel(name string, outChan chan *SenderContent) *Channel {
-	return &Channel{name: name, node: cmap.New[*Client](), outChan: outChan}
+// NewChannel 创建渠道，options 为空时使用默认配置
+func NewChannel(name string, outChan chan *SenderContent, options *ChannelOptions) *Channel {
+	return &Channel{
+		name:    name,
+		node:    cmap.New[*Client](),
+		outChan: outChan,
+		options: options.withDefaults(),
+		users:   make(map[int]map[int64]struct{}),
+	}
 }
 
 // Name 获取渠道名称
@@ -38,9 +52,19 @@ func (c *Channel) Name() string {
 	return c.name
 }
 
+// Options 获取渠道配置
+func (c *Channel) Options() *ChannelOptions {
+	return c.options
+}
+
 // Count 获取客户端连接数
 func (c *Channel) Count() int64 {
-	return c.count
+	return atomic.LoadInt64(&c.count)
+}
+
+// Pending 获取发送通道中待消费的消息数
+func (c *Channel) Pending() int {
+	return len(c.outChan)
 }
 
 // Client 获取客户端
@@ -48,6 +72,19 @@ func (c *Channel) Client(cid int64) (*Client, bool) {
 	return c.node.Get(strconv.FormatInt(cid, 10))
 }
 
+// ClientIds 获取用户在本节点的客户端ID
+func (c *Channel) ClientIds(uid int) []int64 {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	cids := make([]int64, 0, len(c.users[uid]))
+	for cid := range c.users[uid] {
+		cids = append(cids, cid)
+	}
+
+	return cids
+}
+
 // Write 推送消息到消费通道
 func (c *Channel) Write(data *SenderContent) {
 
@@ -65,7 +102,7 @@ func (c *Channel) Write(data *SenderContent) {
 func (c *Channel) Start(ctx context.Context) error {
 
 	var (
-		worker = pool.New().WithMaxGoroutines(10)
+		worker = pool.New().WithMaxGoroutines(c.options.Workers)
 		timer  = time.NewTicker(15 * time.Second)
 	)
 
@@ -84,11 +121,18 @@ func (c *Channel) Start(ctx context.Context) error {
 			}
 
 			c.consume(worker, val, func(data *SenderContent, value *Client) {
+				frame, err := data.frame(value.codec)
+				if err != nil {
+					log.Printf("[ERROR] [%s] channel message marshal err: %v \n", c.name, err)
+					return
+				}
+
 				_ = value.Write(&ClientResponse{
 					IsAck:   data.IsAck,
 					Event:   data.message.Event,
 					Content: data.message.Content,
-					Retry:   3,
+					Retry:   c.options.AckRetry,
+					frame:   frame,
 				})
 			})
 		}
@@ -98,14 +142,33 @@ func (c *Channel) Start(ctx context.Context) error {
 func (c *Channel) consume(worker *pool.Pool, data *SenderContent, fn func(data *SenderContent, value *Client)) {
 	worker.Go(func() {
 
+		exclude := make(map[int64]struct{}, len(data.exclude))
+		for _, cid := range data.exclude {
+			exclude[cid] = struct{}{}
+		}
+
 		if data.IsBroadcast() {
 			c.node.IterCb(func(_ string, client *Client) {
-				fn(data, client)
+				if _, ok := exclude[client.cid]; !ok {
+					fn(data, client)
+				}
 			})
 			return
 		}
 
-		for _, cid := range data.receives {
+		receives := data.receives
+		for _, uid := range data.users {
+			receives = append(receives, c.ClientIds(uid)...)
+		}
+
+		for _, cid := range receives {
+			if _, ok := exclude[cid]; ok {
+				continue
+			}
+
+			// 同一客户端只推送一次
+			exclude[cid] = struct{}{}
+
 			if client, ok := c.Client(cid); ok {
 				fn(data, client)
 			}
@@ -117,6 +180,13 @@ func (c *Channel) consume(worker *pool.Pool, data *SenderContent, fn func(data *
 func (c *Channel) addClient(client *Client) {
 	c.node.Set(strconv.FormatInt(client.cid, 10), client)
 
+	c.mu.Lock()
+	if _, ok := c.users[client.uid]; !ok {
+		c.users[client.uid] = make(map[int64]struct{})
+	}
+	c.users[client.uid][client.cid] = struct{}{}
+	c.mu.Unlock()
+
 	atomic.AddInt64(&c.count, 1)
 }
 
@@ -131,5 +201,12 @@ func (c *Channel) delClient(client *Client) {
 
 	c.node.Remove(cid)
 
+	c.mu.Lock()
+	delete(c.users[client.uid], client.cid)
+	if len(c.users[client.uid]) == 0 {
+		delete(c.users, client.uid)
+	}
+	c.mu.Unlock()
+
 	atomic.AddInt64(&c.count, -1)
 }
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"log"
 	"strings"
+	"sync"
 	"sync/atomic"
 	"time"
 
@@ -34,19 +35,38 @@ type IClient interface {
 	Close(code int, text string)      // 关闭客户端
 	Write(data *ClientResponse) error // 写入数据
 	Channel() IChannel                // 获取客户端所属渠道
+	Meta() *ClientMeta                // 获取客户端连接信息
+	Resume(replay ReplayFunc) error   // 回放离线消息，回放期间暂存实时推送
 }
 
+// ReplayFunc 消息回放函数，write 写入的数据会先于暂存的实时推送发送
+type ReplayFunc func(write func(data *ClientResponse) error) error
+
 // Client WebSocket 客户端连接信息
 type Client struct {
 	conn     IConn                // 客户端连接
 	cid      int64                // 客户端ID/客户端唯一标识
 	uid      int                  // 用户ID
+	meta     *ClientMeta          // 连接信息
 	lastTime int64                // 客户端最后心跳时间/心跳检测
 	closed   int32                // 客户端是否关闭连接
 	channel  IChannel             // 渠道分组
 	storage  IStorage             // 缓存服务
 	event    IEvent               // 回调方法
 	outChan  chan *ClientResponse // 发送通道
+	codec    ICodec               // 消息编解码器
+	limiter  *rateLimiter         // 上行消息限流
+
+	ackStorage IAckStorage // 未确认消息存储
+
+	overflowPolicy OverflowPolicy // 发送缓冲区溢出策略
+	maxOverflow    int            // 断开连接前允许的溢出次数
+	overflows      int32          // 累计溢出次数
+	writeTimeout   time.Duration  // 底层连接写超时
+
+	mu      sync.Mutex        // 保护回放状态
+	holding bool              // 是否正在回放
+	pending []*ClientResponse // 回放期间暂存的实时推送
 }
 
 func (c *Client) Cid() int64 {
@@ -72,9 +92,64 @@ func (c *Client) Close(code int, text string) {
 
 // Write 客户端写入数据
 func (c *Client) Write(data *ClientResponse) error {
+	if c.Closed() {
+		return fmt.Errorf("connection has been closed")
+	}
+
+	if data.IsAck && data.Sid == "" {
+		data.Sid = strings.ReplaceAll(uuid.New().String(), "-", "")
+	}
+
+	c.mu.Lock()
+	if c.holding {
+		defer c.mu.Unlock()
+		return c.hold(data)
+	}
+	c.mu.Unlock()
+
+	return c.push(data)
+}
+
+// Resume 回放离线消息，回放结束后按序补发期间暂存的实时推送
+func (c *Client) Resume(replay ReplayFunc) error {
+	c.mu.Lock()
+	if c.holding {
+		c.mu.Unlock()
+		return fmt.Errorf("client is resuming")
+	}
+	c.holding = true
+	c.mu.Unlock()
+
+	defer c.flush()
+
+	return replay(c.pushWait)
+}
+
+// flush 在锁外按序补发暂存的实时推送，补发期间的新推送继续暂存，全部发出后结束回放
+func (c *Client) flush() {
+	for {
+		c.mu.Lock()
+		pending := c.pending
+		c.pending = nil
+		if len(pending) == 0 {
+			c.holding = false
+			c.mu.Unlock()
+			return
+		}
+		c.mu.Unlock()
+
+		for _, data := range pending {
+			_ = c.pushWait(data)
+		}
+	}
+}
+
+// push 写入发送通道，缓冲区已满时按溢出策略处理
+func (c *Client) push(data *ClientResponse) (err error) {
 	defer func() {
-		if err := recover(); err != nil {
-			log.Printf("[ERROR] [%s-%d-%d] chan write err: %v \n", c.channel.Name(), c.cid, c.uid, err)
+		if e := recover(); e != nil {
+			log.Printf("[ERROR] [%s-%d-%d] chan write err: %v \n", c.channel.Name(), c.cid, c.uid, e)
+			err = fmt.Errorf("connection has been closed")
 		}
 	}()
 
@@ -82,13 +157,37 @@ func (c *Client) Write(data *ClientResponse) error {
 		return fmt.Errorf("connection has been closed")
 	}
 
-	if data.IsAck {
-		data.Sid = strings.ReplaceAll(uuid.New().String(), "-", "")
+	select {
+	case c.outChan <- data:
+		return nil
+	default:
+		return c.overflow(data)
 	}
+}
 
-	c.outChan <- data
+// closeFrame 消息发送后关闭连接的关闭码及原因
+type closeFrame struct {
+	code int
+	text string
+}
 
-	return nil
+// Shutdown 推送最后一条消息后关闭连接，写超时时间内仍未发出时直接关闭
+func (c *Client) Shutdown(data *ClientResponse, code int, text string) {
+	if c.Closed() {
+		return
+	}
+
+	data.close = &closeFrame{code: code, text: text}
+	if err := c.Write(data); err != nil {
+		c.Close(code, text)
+		return
+	}
+
+	time.AfterFunc(c.writeTimeout, func() {
+		if !c.Closed() {
+			c.Close(code, text)
+		}
+	})
 }
 
 // Channel  Name
@@ -96,6 +195,10 @@ func (c *Client) Channel() IChannel {
 	return c.channel
 }
 
+func (c *Client) Meta() *ClientMeta {
+	return c.meta
+}
+
 func (c *Client) Closed() bool {
 	return atomic.LoadInt32(&c.closed) == 1
 }
@@ -125,17 +228,36 @@ func (c *Client) hookClose(code int, text string) error {
 
 }
 
+// ClientMeta 客户端连接信息，绑定时写入缓存供集群查询
+type ClientMeta struct {
+	Network     string `json:"network"`      // 传输方式
+	RemoteAddr  string `json:"remote_addr"`  // 客户端地址
+	Platform    string `json:"platform"`     // 客户端平台
+	Device      string `json:"device"`       // 设备标识
+	ConnectedAt int64  `json:"connected_at"` // 连接时间(毫秒)，多端互踢时按此先后保留最新连接
+}
+
 type IStorage interface {
-	Bind(ctx context.Context, channel string, cid int64, uid int) error
+	Bind(ctx context.Context, channel string, cid int64, uid int, meta *ClientMeta) error
 	UnBind(ctx context.Context, channel string, cid int64) error
 }
 
 type ClientOption struct {
-	Uid         int         // 用户识别ID
-	Channel     IChannel    // 渠道信息
-	Storage     IStorage    // 自定义缓存组件，用于绑定用户与客户端的关系
-	IdGenerator IdGenerator // 客户端ID生成器(唯一ID), 默认使用雪花算法
-	Buffer      int         // 缓冲区大小根据业务，自行调整
+	Uid        int         // 用户识别ID
+	Channel    IChannel    // 渠道信息
+	RemoteAddr string      // 客户端地址
+	Platform   string      // 客户端平台
+	Device     string      // 设备标识
+	Storage    IStorage    // 自定义缓存组件，用于绑定用户与客户端的关系
+	AckStorage IAckStorage // 未确认消息存储，为空时仅在当前连接内重试
+
+	OverflowPolicy OverflowPolicy    // 发送缓冲区已满时的处理策略，默认丢弃当前消息
+	MaxOverflow    int               // disconnect 策略下断开连接前允许的溢出次数，默认 10
+	WriteTimeout   time.Duration     // 底层连接写超时，默认 10 秒
+	Codec          ICodec            // 消息编解码器，默认 JSON
+	RateLimit      *RateLimitOptions // 上行消息限流，为空时不限制
+	IdGenerator    IdGenerator       // 客户端ID生成器(唯一ID), 默认使用雪花算法
+	Buffer         int               // 缓冲区大小根据业务，自行调整
 }
 
 type ClientResponse struct {
@@ -144,6 +266,17 @@ type ClientResponse struct {
 	Event   string `json:"event"`             // 事件名
 	Content any    `json:"content,omitempty"` // 事件内容
 	Retry   int    `json:"-"`                 // 重试次数（0 默认不重试）
+
+	// 请求响应，仅 rpc.reply 使用
+	ReqId string `json:"req_id,omitempty"` // 客户端请求ID
+	Code  *int   `json:"code,omitempty"`   // 错误码，0 表示成功
+	Msg   string `json:"msg,omitempty"`    // 错误描述
+	Data  any    `json:"data,omitempty"`   // 返回数据
+
+	attempts int         // 已发送次数
+	stored   bool        // 是否已持久化
+	close    *closeFrame // 发送后关闭连接
+	frame    []byte      // 客户端编码格式下预编码的消息体（不含 ACK ID），多个客户端共享，不可修改
 }
 
 // NewClient 初始化
@@ -152,6 +285,18 @@ func NewClient(conn IConn, option *ClientOption, event IEvent) error {
 		option.Buffer = 10
 	}
 
+	if option.MaxOverflow <= 0 {
+		option.MaxOverflow = defaultMaxOverflow
+	}
+
+	if option.WriteTimeout <= 0 {
+		option.WriteTimeout = defaultWriteTimeout
+	}
+
+	if option.Codec == nil {
+		option.Codec = jsonCodec{}
+	}
+
 	if event == nil {
 		panic("event can't be nil!")
 	}
@@ -160,10 +305,25 @@ func NewClient(conn IConn, option *ClientOption, event IEvent) error {
 		conn:     conn,
 		uid:      option.Uid,
 		lastTime: time.Now().Unix(),
-		channel:  option.Channel,
-		storage:  option.Storage,
-		outChan:  make(chan *ClientResponse, option.Buffer),
-		event:    event,
+		meta: &ClientMeta{
+			Network:     conn.Network(),
+			RemoteAddr:  option.RemoteAddr,
+			Platform:    option.Platform,
+			Device:      option.Device,
+			ConnectedAt: time.Now().UnixMilli(),
+		},
+		channel: option.Channel,
+		storage: option.Storage,
+		outChan: make(chan *ClientResponse, option.Buffer),
+		codec:   option.Codec,
+		limiter: newRateLimiter(option.RateLimit),
+		event:   event,
+
+		ackStorage: option.AckStorage,
+
+		overflowPolicy: option.OverflowPolicy,
+		maxOverflow:    option.MaxOverflow,
+		writeTimeout:   option.WriteTimeout,
 	}
 
 	if option.IdGenerator != nil {
@@ -175,7 +335,7 @@ func NewClient(conn IConn, option *ClientOption, event IEvent) error {
 	conn.SetCloseHandler(client.hookClose)
 
 	if client.storage != nil {
-		err := client.storage.Bind(context.TODO(), client.channel.Name(), client.cid, client.uid)
+		err := client.storage.Bind(context.TODO(), client.channel.Name(), client.cid, client.uid, client.meta)
 		if err != nil {
 			logger.Error("[ERROR] bind client err: ", logger.Err(err))
 			return err
@@ -194,18 +354,26 @@ func NewClient(conn IConn, option *ClientOption, event IEvent) error {
 // 初始化连接
 func (c *Client) init() error {
 
-	// 推送心跳检测配置
+	// 推送心跳检测及 ACK 配置
+	options := c.channel.Options()
 	_ = c.Write(&ClientResponse{
 		Event: "connect",
 		Content: map[string]any{
-			"ping_interval": heartbeatInterval,
-			"ping_timeout":  heartbeatTimeout,
+			"ping_interval": int(options.HeartbeatInterval.Seconds()),
+			"ping_timeout":  int(options.HeartbeatTimeout.Seconds()),
+			"ack_delay":     int(options.AckDelay.Seconds()),
+			"ack_retry":     options.AckRetry,
 		}},
 	)
 
 	// 启动协程处理推送信息
 	go c.loopWrite()
 
+	// 重投上次连接未确认的消息
+	if ack != nil {
+		ack.redeliver(c)
+	}
+
 	go c.loopAccept()
 
 	return nil
@@ -244,27 +412,30 @@ func (c *Client) loopWrite() {
 				return // channel closed
 			}
 
-			bt, err := sonic.Marshal(data)
+			bt, err := c.codec.Marshal(data)
 			if err != nil {
-				logger.Error("[ERROR] client json marshal err: %v \n", logger.Err(err))
+				logger.Error("[ERROR] client marshal err: %v \n", logger.Err(err))
 				break
 			}
 
+			_ = c.conn.SetWriteDeadline(time.Now().Add(c.writeTimeout))
 			if err := c.conn.Write(bt); err != nil {
 				log.Printf("[ERROR] [%s-%d-%d] client write err: %v \n", c.channel.Name(), c.cid, c.uid, err)
+
+				if isTimeout(err) {
+					overflowCounter.WithLabelValues(c.channel.Name(), overflowWriteTimeout).Inc()
+					c.Close(CloseCodeSlowConsumer, "写入超时，连接已关闭")
+				}
 				return
 			}
 
-			if data.IsAck && data.Retry > 0 {
-				data.Retry--
-
-				ackBufferContent := &AckBufferContent{}
-				ackBufferContent.cid = c.cid
-				ackBufferContent.uid = int64(c.uid)
-				ackBufferContent.channel = c.channel.Name()
-				ackBufferContent.response = data
+			if data.IsAck && ack != nil {
+				ack.insert(c, data)
+			}
 
-				ack.insert(data.Sid, ackBufferContent)
+			if data.close != nil {
+				c.Close(data.close.code, data.close.text)
+				return
 			}
 		}
 	}
@@ -272,12 +443,29 @@ func (c *Client) loopWrite() {
 
 func (c *Client) handleMessage(data []byte) {
 
+	if c.limiter != nil && c.limiter.options.MaxFrameSize > 0 && len(data) > c.limiter.options.MaxFrameSize {
+		c.limiter.violate(c, "", "frame_too_large")
+		return
+	}
+
+	data, err := c.codec.Unmarshal(data)
+	if err != nil {
+		logger.Error("[ERROR] client unmarshal err: ", logger.Err(err))
+		return
+	}
+
 	event, err := c.validate(data)
 	if err != nil {
 		logger.Error("[ERROR] validate err: %s \n", logger.Err(err))
 		return
 	}
 
+	// ack 不限流，避免消息重复投递
+	if c.limiter != nil && event != _MsgEventAck && !c.limiter.allow(c, event) {
+		c.limiter.violate(c, event, "rate_limited")
+		return
+	}
+
 	switch event {
 	case _MsgEventPing:
 		_ = c.Write(&ClientResponse{Event: _MsgEventPong})
@@ -286,8 +474,8 @@ func (c *Client) handleMessage(data []byte) {
 		val, err := sonic.Get(data, sid)
 		if err == nil {
 			ackId, _ := val.String()
-			if len(ackId) > 0 {
-				ack.delete(ackId)
+			if len(ackId) > 0 && ack != nil {
+				ack.delete(c, ackId)
 			}
 		}
 
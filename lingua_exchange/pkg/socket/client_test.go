@@ -0,0 +1,365 @@
+package socket
+
+import (
+	"context"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sourcegraph/conc/pool"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient_Resume(t *testing.T) {
+	c := &Client{
+		channel:      NewChannel("test", make(chan *SenderContent), nil),
+		outChan:      make(chan *ClientResponse, 10),
+		writeTimeout: time.Second,
+	}
+
+	err := c.Resume(func(write func(data *ClientResponse) error) error {
+		assert.NoError(t, write(&ClientResponse{Event: "replay.1"}))
+
+		// 回放期间的实时推送需在回放结束后发送
+		assert.NoError(t, c.Write(&ClientResponse{Event: "live"}))
+		assert.Error(t, c.Resume(func(write func(data *ClientResponse) error) error { return nil }))
+
+		return write(&ClientResponse{Event: "replay.2"})
+	})
+	assert.NoError(t, err)
+	assert.NoError(t, c.Write(&ClientResponse{Event: "after"}))
+
+	close(c.outChan)
+
+	var events []string
+	for data := range c.outChan {
+		events = append(events, data.Event)
+	}
+
+	assert.Equal(t, []string{"replay.1", "replay.2", "live", "after"}, events)
+
+	// 暂存的实时推送超过上限时按溢出策略丢弃最早的消息
+	c.outChan = make(chan *ClientResponse, maxPending+1)
+	c.overflowPolicy = OverflowDropOldest
+	err = c.Resume(func(write func(data *ClientResponse) error) error {
+		for i := 0; i <= maxPending; i++ {
+			assert.NoError(t, c.Write(&ClientResponse{Event: strconv.Itoa(i)}))
+		}
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Len(t, c.outChan, maxPending)
+	assert.Equal(t, "1", (<-c.outChan).Event)
+}
+
+// memoryAckStorage 未确认消息按 ACK ID 顺序返回，忽略消息归属
+type memoryAckStorage map[string][]byte
+
+func (m memoryAckStorage) Save(_ context.Context, _ *AckScope, sid string, data []byte) error {
+	m[sid] = data
+	return nil
+}
+
+func (m memoryAckStorage) Delete(_ context.Context, _ *AckScope, sid string) error {
+	delete(m, sid)
+	return nil
+}
+
+func (m memoryAckStorage) All(_ context.Context, _ *AckScope) ([]*AckMessage, error) {
+	items := make([]*AckMessage, 0, len(m))
+	for sid, data := range m {
+		items = append(items, &AckMessage{Sid: sid, Data: data})
+	}
+
+	sort.Slice(items, func(i, j int) bool { return items[i].Sid < items[j].Sid })
+	return items, nil
+}
+
+func TestAckBuffer_Redeliver(t *testing.T) {
+	InitAck()
+
+	storage := memoryAckStorage{}
+	c := &Client{
+		uid:          1,
+		channel:      NewChannel("test", make(chan *SenderContent), nil),
+		outChan:      make(chan *ClientResponse, 10),
+		ackStorage:   storage,
+		writeTimeout: time.Second,
+	}
+
+	// 首次发送后持久化，确认后删除
+	data := &ClientResponse{IsAck: true, Sid: "a", Event: "im.message", Content: "hello", Retry: 3}
+	ack.insert(c, data)
+	ack.insert(c, &ClientResponse{IsAck: true, Sid: "b", Event: "im.message", Retry: 3})
+	assert.Len(t, storage, 2)
+
+	ack.insert(c, &ClientResponse{IsAck: true, Sid: "c", Event: "im.message", Retry: 3})
+	assert.Len(t, storage, 3)
+
+	ack.delete(c, "b")
+	assert.Len(t, storage, 2)
+
+	// 新连接按存储顺序重投，保持原 ACK ID
+	ack.redeliver(c)
+	out := <-c.outChan
+	assert.Equal(t, "a", out.Sid)
+	assert.Equal(t, "hello", out.Content)
+	assert.True(t, out.IsAck && out.stored)
+	assert.Equal(t, "c", (<-c.outChan).Sid)
+
+	c.meta = &ClientMeta{Device: "d1"}
+	assert.Equal(t, &AckScope{Uid: 1, Channel: "test", Device: "d1"}, c.ackScope())
+
+	options := c.channel.Options()
+	assert.Equal(t, options.AckDelay, options.ackDelay(1))
+	assert.Equal(t, 2*options.AckDelay, options.ackDelay(2))
+	assert.Equal(t, options.AckMaxDelay, options.ackDelay(10))
+}
+
+func TestChannel_Consume(t *testing.T) {
+	ch := NewChannel("test", make(chan *SenderContent), nil)
+
+	clients := make([]*Client, 0)
+	for cid, uid := range []int{1, 1, 2, 3} {
+		c := &Client{cid: int64(cid + 1), uid: uid, channel: ch, outChan: make(chan *ClientResponse, 10)}
+		ch.addClient(c)
+		clients = append(clients, c)
+	}
+
+	assert.ElementsMatch(t, []int64{1, 2}, ch.ClientIds(1))
+
+	received := func(data *SenderContent) []int64 {
+		var (
+			mu   sync.Mutex
+			cids []int64
+		)
+
+		worker := pool.New()
+		ch.consume(worker, data, func(_ *SenderContent, c *Client) {
+			mu.Lock()
+			cids = append(cids, c.cid)
+			mu.Unlock()
+		})
+		worker.Wait()
+
+		return cids
+	}
+
+	// 按用户推送并排除指定客户端，重复的客户端只推送一次
+	data := NewSenderContent().SetReceiveUsers(1, 2).SetReceive(3).SetExclude(2)
+	assert.ElementsMatch(t, []int64{1, 3}, received(data))
+
+	// 广播同样遵循排除列表
+	data = NewSenderContent().SetBroadcast(true).SetExclude(1, 4)
+	assert.ElementsMatch(t, []int64{2, 3}, received(data))
+
+	ch.delClient(clients[0])
+	assert.Equal(t, []int64{2}, ch.ClientIds(1))
+}
+
+func TestClient_Overflow(t *testing.T) {
+	newClient := func(policy OverflowPolicy) *Client {
+		return &Client{
+			channel:        NewChannel("test", make(chan *SenderContent), nil),
+			outChan:        make(chan *ClientResponse, 1),
+			overflowPolicy: policy,
+			maxOverflow:    defaultMaxOverflow,
+		}
+	}
+
+	// 丢弃当前消息
+	c := newClient(OverflowDropNewest)
+	assert.NoError(t, c.Write(&ClientResponse{Event: "1"}))
+	assert.ErrorIs(t, c.Write(&ClientResponse{Event: "2"}), errClientOverflow)
+	assert.Equal(t, "1", (<-c.outChan).Event)
+
+	// 丢弃最早的消息
+	c = newClient(OverflowDropOldest)
+	assert.NoError(t, c.Write(&ClientResponse{Event: "1"}))
+	assert.NoError(t, c.Write(&ClientResponse{Event: "2"}))
+	assert.Equal(t, "2", (<-c.outChan).Event)
+
+	// 被丢弃的需确认消息持久化，等待下次连接重投
+	storage := memoryAckStorage{}
+	c = newClient(OverflowDropOldest)
+	c.ackStorage = storage
+	assert.NoError(t, c.Write(&ClientResponse{IsAck: true, Sid: "a", Event: "1"}))
+	assert.NoError(t, c.Write(&ClientResponse{IsAck: true, Sid: "b", Event: "2"}))
+	assert.Contains(t, storage, "a")
+	assert.NotContains(t, storage, "b")
+
+	c = newClient(OverflowDropNewest)
+	c.ackStorage = storage
+	assert.NoError(t, c.Write(&ClientResponse{Event: "1"}))
+	assert.ErrorIs(t, c.Write(&ClientResponse{IsAck: true, Sid: "c", Event: "2"}), errClientOverflow)
+	assert.Contains(t, storage, "c")
+}
+
+func TestClient_OverflowDisconnect(t *testing.T) {
+	conn := &nopConn{}
+	closed := make(chan int, 1)
+	c := &Client{
+		conn:           conn,
+		channel:        NewChannel("test", make(chan *SenderContent), nil),
+		outChan:        make(chan *ClientResponse, 1),
+		overflowPolicy: OverflowDisconnect,
+		maxOverflow:    2,
+		event: NewEvent(WithCloseEvent(func(client IClient, code int, text string) {
+			closed <- code
+		})),
+	}
+
+	// 溢出次数未达到上限时只丢弃当前消息
+	assert.NoError(t, c.Write(&ClientResponse{Event: "1"}))
+	assert.ErrorIs(t, c.Write(&ClientResponse{Event: "2"}), errClientOverflow)
+	assert.False(t, c.Closed())
+
+	// 达到上限后以 4008 断开连接，之后的溢出不再重复关闭
+	assert.ErrorIs(t, c.Write(&ClientResponse{Event: "3"}), errClientOverflow)
+	assert.Equal(t, CloseCodeSlowConsumer, <-closed)
+	assert.True(t, c.Closed())
+	assert.ErrorIs(t, c.overflow(&ClientResponse{Event: "4"}), errClientOverflow)
+	assert.Len(t, closed, 0)
+}
+
+func TestChannelOptions_WithDefaults(t *testing.T) {
+	assert.Equal(t, DefaultChannelOptions(), (*ChannelOptions)(nil).withDefaults())
+
+	options := (&ChannelOptions{HeartbeatInterval: 10 * time.Second, AckDelay: 2 * time.Second}).withDefaults()
+	assert.Equal(t, 25*time.Second, options.HeartbeatTimeout)
+	assert.Equal(t, 60*time.Second, options.AckMaxDelay)
+	assert.Equal(t, 3, options.AckRetry)
+
+	// 超时时间不足两个心跳间隔时提升为两个间隔
+	options = (&ChannelOptions{HeartbeatInterval: 10 * time.Second, HeartbeatTimeout: 15 * time.Second}).withDefaults()
+	assert.Equal(t, 20*time.Second, options.HeartbeatTimeout)
+
+	options = (&ChannelOptions{HeartbeatInterval: 10 * time.Second, HeartbeatTimeout: 40 * time.Second}).withDefaults()
+	assert.Equal(t, 40*time.Second, options.HeartbeatTimeout)
+}
+
+func TestSession_Register(t *testing.T) {
+	s := &session{channels: map[string]*Channel{}}
+
+	_, err := s.Register("chat", 0, nil)
+	assert.NoError(t, err)
+
+	// 回调覆盖已注册及之后注册的渠道
+	var names []string
+	s.Watch(func(ch *Channel) { names = append(names, ch.Name()) })
+
+	ch, err := s.Register("practice", 0, &ChannelOptions{Workers: 2})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, ch.Options().Workers)
+	assert.Equal(t, defaultChannelBuffer, cap(ch.outChan))
+
+	_, err = s.Register("practice", 0, nil)
+	assert.Error(t, err)
+
+	assert.Equal(t, []string{"chat", "practice"}, names)
+	assert.Len(t, s.Channels(), 2)
+}
+
+type nopConn struct{ closed bool }
+
+func (n *nopConn) Read() ([]byte, error)                   { return nil, io.EOF }
+func (n *nopConn) Write([]byte) error                      { return nil }
+func (n *nopConn) Close() error                            { n.closed = true; return nil }
+func (n *nopConn) SetCloseHandler(func(int, string) error) {}
+func (n *nopConn) SetWriteDeadline(time.Time) error        { return nil }
+func (n *nopConn) Network() string                         { return "nop" }
+
+type denyUserLimiter struct{}
+
+func (denyUserLimiter) Allow(context.Context, int, string) (bool, error) { return false, nil }
+
+func TestClient_RateLimit(t *testing.T) {
+	conn := &nopConn{}
+	c := &Client{
+		conn:    conn,
+		channel: NewChannel("test", make(chan *SenderContent), nil),
+		outChan: make(chan *ClientResponse, 10),
+		codec:   jsonCodec{},
+		event:   NewEvent(),
+		limiter: newRateLimiter(&RateLimitOptions{
+			MaxFrameSize: 64,
+			Rules:        map[string]RateRule{"ping": {Rate: 0.001, Burst: 1}},
+			WarnAfter:    2,
+			CloseAfter:   4,
+		}),
+	}
+
+	// 首条消息正常处理，之后依次丢弃、告警、断开
+	c.handleMessage([]byte(`{"event":"ping"}`))
+	assert.Equal(t, _MsgEventPong, (<-c.outChan).Event)
+
+	c.handleMessage([]byte(`{"event":"ping"}`))
+	assert.Len(t, c.outChan, 0)
+
+	c.handleMessage([]byte(`{"event":"ping","content":"` + strings.Repeat("x", 64) + `"}`))
+	assert.Equal(t, PushEventRateLimited, (<-c.outChan).Event)
+
+	// ack 及未配置规则的事件不限流
+	c.handleMessage([]byte(`{"event":"ack","sid":""}`))
+	assert.False(t, c.Closed())
+
+	c.handleMessage([]byte(`{"event":"ping"}`))
+	assert.Equal(t, PushEventRateLimited, (<-c.outChan).Event)
+
+	c.handleMessage([]byte(`{"event":"ping"}`))
+	assert.True(t, c.Closed() && conn.closed)
+
+	// 用户级限流
+	c = &Client{codec: jsonCodec{}, limiter: newRateLimiter(&RateLimitOptions{UserLimiter: denyUserLimiter{}})}
+	assert.False(t, c.limiter.allow(c, "im.message.publish"))
+
+	// 未配置规则的事件共用 * 令牌桶，轮换事件名无法绕过限流
+	c = &Client{codec: jsonCodec{}, limiter: newRateLimiter(&RateLimitOptions{
+		Rules: map[string]RateRule{"*": {Rate: 0.001, Burst: 2}, "ping": {Rate: 0.001, Burst: 1}},
+	})}
+	assert.True(t, c.limiter.allow(c, "a"))
+	assert.True(t, c.limiter.allow(c, "b"))
+	assert.False(t, c.limiter.allow(c, "c"))
+	assert.True(t, c.limiter.allow(c, "ping"))
+	assert.Len(t, c.limiter.buckets, 2)
+}
+
+type recordConn struct {
+	nopConn
+	writes chan []byte
+}
+
+func (r *recordConn) Write(data []byte) error {
+	r.writes <- data
+	return nil
+}
+
+func TestClient_Shutdown(t *testing.T) {
+	conn := &recordConn{writes: make(chan []byte, 10)}
+	closed := make(chan int, 1)
+	c := &Client{
+		conn:         conn,
+		channel:      NewChannel("test", make(chan *SenderContent), nil),
+		outChan:      make(chan *ClientResponse, 10),
+		codec:        jsonCodec{},
+		writeTimeout: time.Second,
+		event: NewEvent(WithCloseEvent(func(client IClient, code int, text string) {
+			closed <- code
+		})),
+	}
+	go c.loopWrite()
+
+	// 先发出最后一条消息再关闭连接
+	c.Shutdown(&ClientResponse{Event: "kicked"}, 4003, "kicked")
+	assert.JSONEq(t, `{"event":"kicked"}`, string(<-conn.writes))
+	assert.Equal(t, 4003, <-closed)
+	assert.True(t, c.Closed())
+
+	// 已关闭的连接直接返回
+	c.Shutdown(&ClientResponse{Event: "kicked"}, 4003, "kicked")
+	assert.Len(t, conn.writes, 0)
+}
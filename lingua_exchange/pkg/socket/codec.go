@@ -0,0 +1,138 @@
+package socket
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+
+	"github.com/bytedance/sonic"
+	"github.com/vmihailenco/msgpack"
+)
+
+// 消息编码格式，同时作为 WebSocket 子协议名称
+const (
+	CodecJSON     = "json"
+	CodecMsgPack  = "msgpack"
+	CodecProtobuf = "protobuf"
+)
+
+// ICodec 客户端消息编解码器，连接建立时由客户端协商
+type ICodec interface {
+	Name() string                                 // 编码格式名称
+	Binary() bool                                 // 是否使用二进制帧
+	Marshal(data *ClientResponse) ([]byte, error) // 编码下行消息
+	Unmarshal(data []byte) ([]byte, error)        // 将上行消息转换为 JSON，交由事件回调处理
+}
+
+var codecs = map[string]ICodec{
+	CodecJSON:     jsonCodec{},
+	CodecMsgPack:  msgpackCodec{},
+	CodecProtobuf: protobufCodec{},
+}
+
+// Codec 获取编解码器，name 为空时使用 JSON
+func Codec(name string) (ICodec, bool) {
+	if name == "" {
+		name = CodecJSON
+	}
+
+	codec, ok := codecs[name]
+	return codec, ok
+}
+
+// Codecs 获取支持的编码格式
+func Codecs() []string {
+	names := make([]string, 0, len(codecs))
+	for name := range codecs {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+	return names
+}
+
+// jsonCodec JSON 编解码（默认）
+type jsonCodec struct{}
+
+func (jsonCodec) Name() string {
+	return CodecJSON
+}
+
+func (jsonCodec) Binary() bool {
+	return false
+}
+
+func (jsonCodec) Marshal(data *ClientResponse) ([]byte, error) {
+	return data.encode()
+}
+
+func (jsonCodec) Unmarshal(data []byte) ([]byte, error) {
+	return data, nil
+}
+
+// msgpackCodec MessagePack 编解码，字段名与 JSON 一致
+type msgpackCodec struct{}
+
+func (msgpackCodec) Name() string {
+	return CodecMsgPack
+}
+
+func (msgpackCodec) Binary() bool {
+	return true
+}
+
+func (msgpackCodec) Marshal(data *ClientResponse) ([]byte, error) {
+	// 预编码数据为不含 sid 的 fixmap，拼接 sid 时仅需修改字段数
+	if len(data.frame) > 0 && data.frame[0] >= 0x80 && data.frame[0] < 0x8f {
+		if data.Sid == "" {
+			return data.frame, nil
+		}
+
+		var buf bytes.Buffer
+		buf.Grow(len(data.frame) + len(data.Sid) + 8)
+		buf.WriteByte(data.frame[0] + 1)
+
+		if err := msgpack.NewEncoder(&buf).EncodeMulti("sid", data.Sid); err != nil {
+			return nil, err
+		}
+
+		buf.Write(data.frame[1:])
+
+		return buf.Bytes(), nil
+	}
+
+	content, err := decodeRawContent(data.Content)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	err = msgpack.NewEncoder(&buf).UseJSONTag(true).Encode(&ClientResponse{
+		Sid:     data.Sid,
+		Event:   data.Event,
+		Content: content,
+	})
+
+	return buf.Bytes(), err
+}
+
+func (msgpackCodec) Unmarshal(data []byte) ([]byte, error) {
+	var value any
+	if err := msgpack.Unmarshal(data, &value); err != nil {
+		return nil, err
+	}
+
+	return sonic.Marshal(value)
+}
+
+// decodeRawContent 将 JSON 原始数据还原为通用结构，避免被编码为二进制
+func decodeRawContent(content any) (any, error) {
+	raw, ok := content.(json.RawMessage)
+	if !ok {
+		return content, nil
+	}
+
+	var value any
+	err := sonic.Unmarshal(raw, &value)
+	return value, err
+}
@@ -0,0 +1,172 @@
+package socket
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/bytedance/sonic"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/dynamicpb"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// 消息字段编号，定义见 proto/socket.proto
+const (
+	protoFieldSid     protowire.Number = 1
+	protoFieldEvent   protowire.Number = 2
+	protoFieldContent protowire.Number = 3
+	protoFieldPayload protowire.Number = 4
+	protoFieldReqId   protowire.Number = 5
+	protoFieldCode    protowire.Number = 6
+	protoFieldMsg     protowire.Number = 7
+	protoFieldData    protowire.Number = 8
+)
+
+// protoPayloads 按事件注册的消息内容类型
+var protoPayloads sync.Map
+
+// RegisterProtoPayload 注册事件内容的 Protobuf 消息类型
+// 已注册的事件内容编码到 payload 字段，未注册的使用 google.protobuf.Value 编码到 content 字段
+func RegisterProtoPayload(event string, desc protoreflect.MessageDescriptor) {
+	protoPayloads.Store(event, desc)
+}
+
+// protobufCodec Protobuf 编解码
+type protobufCodec struct{}
+
+func (protobufCodec) Name() string {
+	return CodecProtobuf
+}
+
+func (protobufCodec) Binary() bool {
+	return true
+}
+
+func (protobufCodec) Marshal(data *ClientResponse) ([]byte, error) {
+	// 字段与顺序无关，在预编码数据前拼接 sid 即可
+	if len(data.frame) > 0 {
+		if data.Sid == "" {
+			return data.frame, nil
+		}
+
+		bt := make([]byte, 0, len(data.frame)+len(data.Sid)+2)
+		bt = appendProtoString(bt, protoFieldSid, data.Sid)
+		return append(bt, data.frame...), nil
+	}
+
+	var bt []byte
+	if data.Sid != "" {
+		bt = appendProtoString(bt, protoFieldSid, data.Sid)
+	}
+
+	bt = appendProtoString(bt, protoFieldEvent, data.Event)
+
+	if data.ReqId != "" {
+		bt = appendProtoString(bt, protoFieldReqId, data.ReqId)
+	}
+
+	if data.Code != nil {
+		bt = protowire.AppendTag(bt, protoFieldCode, protowire.VarintType)
+		bt = protowire.AppendVarint(bt, uint64(int64(*data.Code)))
+	}
+
+	if data.Msg != "" {
+		bt = appendProtoString(bt, protoFieldMsg, data.Msg)
+	}
+
+	var err error
+	if data.Data != nil {
+		if bt, err = appendProtoValue(bt, protoFieldData, "", data.Data); err != nil {
+			return nil, err
+		}
+	}
+
+	if data.Content != nil {
+		if bt, err = appendProtoValue(bt, protoFieldContent, data.Event, data.Content); err != nil {
+			return nil, err
+		}
+	}
+
+	return bt, nil
+}
+
+// appendProtoValue 编码事件内容，event 已注册类型时编码到 payload 字段，否则使用 google.protobuf.Value
+func appendProtoValue(bt []byte, number protowire.Number, event string, value any) ([]byte, error) {
+	raw, ok := value.(json.RawMessage)
+	if !ok {
+		var err error
+		if raw, err = sonic.Marshal(value); err != nil {
+			return nil, err
+		}
+	}
+
+	msg := proto.Message(&structpb.Value{})
+	if desc, ok := protoPayloads.Load(event); ok && event != "" {
+		number, msg = protoFieldPayload, dynamicpb.NewMessage(desc.(protoreflect.MessageDescriptor))
+	}
+
+	if err := (protojson.UnmarshalOptions{DiscardUnknown: true}).Unmarshal(raw, msg); err != nil {
+		return nil, fmt.Errorf("protobuf encode %s content err: %w", event, err)
+	}
+
+	content, err := proto.Marshal(msg)
+	if err != nil {
+		return nil, err
+	}
+
+	bt = protowire.AppendTag(bt, number, protowire.BytesType)
+	return protowire.AppendBytes(bt, content), nil
+}
+
+// Unmarshal 上行消息内容统一使用 content 字段
+func (protobufCodec) Unmarshal(data []byte) ([]byte, error) {
+	msg := make(map[string]any, 3)
+
+	for len(data) > 0 {
+		number, typ, n := protowire.ConsumeTag(data)
+		if n < 0 {
+			return nil, protowire.ParseError(n)
+		}
+		data = data[n:]
+
+		if typ != protowire.BytesType {
+			if n = protowire.ConsumeFieldValue(number, typ, data); n < 0 {
+				return nil, protowire.ParseError(n)
+			}
+			data = data[n:]
+			continue
+		}
+
+		val, n := protowire.ConsumeBytes(data)
+		if n < 0 {
+			return nil, protowire.ParseError(n)
+		}
+		data = data[n:]
+
+		switch number {
+		case protoFieldSid:
+			msg["sid"] = string(val)
+		case protoFieldEvent:
+			msg["event"] = string(val)
+		case protoFieldReqId:
+			msg["req_id"] = string(val)
+		case protoFieldContent:
+			value := &structpb.Value{}
+			if err := proto.Unmarshal(val, value); err != nil {
+				return nil, err
+			}
+			msg["content"] = value.AsInterface()
+		}
+	}
+
+	return sonic.Marshal(msg)
+}
+
+func appendProtoString(bt []byte, number protowire.Number, value string) []byte {
+	bt = protowire.AppendTag(bt, number, protowire.BytesType)
+	return protowire.AppendString(bt, value)
+}
@@ -0,0 +1,59 @@
+package socket
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vmihailenco/msgpack"
+)
+
+func TestCodec_Frame(t *testing.T) {
+	for _, name := range Codecs() {
+		codec, _ := Codec(name)
+		data := benchSenderContent()
+
+		frame, err := data.frame(codec)
+		assert.NoError(t, err)
+
+		// 拼接 ACK ID 的结果与完整编码一致
+		resp := &ClientResponse{Sid: "a1b2c3", Event: data.message.Event, Content: data.message.Content}
+		expect, err := codec.Marshal(resp)
+		assert.NoError(t, err)
+
+		resp.frame = frame
+		actual, err := codec.Marshal(resp)
+		assert.NoError(t, err)
+
+		expectJSON, err := decodeFrame(codec, expect)
+		assert.NoError(t, err, name)
+
+		actualJSON, err := decodeFrame(codec, actual)
+		assert.NoError(t, err, name)
+		assert.JSONEq(t, expectJSON, actualJSON, name)
+	}
+}
+
+func TestCodec_Unmarshal(t *testing.T) {
+	body, err := msgpack.Marshal(map[string]any{"event": "ping", "content": map[string]any{"id": 1}})
+	assert.NoError(t, err)
+
+	data, err := msgpackCodec{}.Unmarshal(body)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"event":"ping","content":{"id":1}}`, string(data))
+
+	body, err = protobufCodec{}.Marshal(&ClientResponse{Sid: "x", Event: "ping", Content: map[string]any{"id": 1}})
+	assert.NoError(t, err)
+
+	data, err = protobufCodec{}.Unmarshal(body)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"sid":"x","event":"ping","content":{"id":1}}`, string(data))
+
+	_, ok := Codec("xml")
+	assert.False(t, ok)
+}
+
+// decodeFrame 将下行消息还原为 JSON 用于比较
+func decodeFrame(codec ICodec, data []byte) (string, error) {
+	bt, err := codec.Unmarshal(data)
+	return string(bt), err
+}
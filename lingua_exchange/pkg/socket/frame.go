@@ -0,0 +1,73 @@
+package socket
+
+import (
+	"sync"
+
+	"github.com/bytedance/sonic"
+)
+
+// encodedFrame 预编码的消息体
+type encodedFrame struct {
+	once sync.Once
+	data []byte
+	err  error
+}
+
+// frame 获取消息的预编码数据，同一条推送消息按编码格式只序列化一次，由所有接收客户端共享
+func (s *SenderContent) frame(codec ICodec) ([]byte, error) {
+	val, ok := s.frames.Load(codec.Name())
+	if !ok {
+		val, _ = s.frames.LoadOrStore(codec.Name(), &encodedFrame{})
+	}
+
+	f := val.(*encodedFrame)
+	f.once.Do(func() {
+		f.data, f.err = codec.Marshal(&ClientResponse{
+			Event:   s.message.Event,
+			Content: s.message.Content,
+		})
+	})
+
+	return f.data, f.err
+}
+
+// encode 序列化 JSON 推送数据，存在预编码数据时仅拼接客户端各自的 ACK ID
+func (c *ClientResponse) encode() ([]byte, error) {
+	if len(c.frame) < 2 {
+		return sonic.Marshal(c)
+	}
+
+	if c.Sid == "" {
+		return c.frame, nil
+	}
+
+	// {"sid":"xxx","event":...}
+	bt := make([]byte, 0, len(c.frame)+len(c.Sid)+10)
+	bt = append(bt, `{"sid":`...)
+
+	if isPlainString(c.Sid) {
+		bt = append(append(append(bt, '"'), c.Sid...), '"')
+	} else {
+		sid, err := sonic.Marshal(c.Sid)
+		if err != nil {
+			return nil, err
+		}
+		bt = append(bt, sid...)
+	}
+
+	bt = append(bt, ',')
+	bt = append(bt, c.frame[1:]...)
+
+	return bt, nil
+}
+
+// isPlainString 判断字符串是否无需 JSON 转义
+func isPlainString(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < 0x20 || s[i] >= 0x7f || s[i] == '"' || s[i] == '\\' {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,95 @@
+package socket
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/bytedance/sonic"
+	"github.com/stretchr/testify/assert"
+)
+
+// 模拟群聊消息推送内容
+func benchSenderContent() *SenderContent {
+	return NewSenderContent().SetAck(true).SetBroadcast(true).SetMessage("im.message", map[string]any{
+		"to_from_id": 10001,
+		"from_id":    2054,
+		"talk_type":  2,
+		"body": map[string]any{
+			"msg_id":     "7c0e4d2a9b6f4a3e8d1c5b2a7f9e0d3c",
+			"sequence":   18230,
+			"msg_type":   1,
+			"user_id":    2054,
+			"nickname":   "linguist",
+			"avatar":     "https://example.com/avatar/2054.png",
+			"is_revoke":  0,
+			"content":    "Ich lerne seit drei Monaten Deutsch, kann jemand meinen Text korrigieren?",
+			"created_at": "2024-06-01 12:30:45",
+		},
+	})
+}
+
+func TestClientResponse_Encode(t *testing.T) {
+	data := benchSenderContent()
+
+	frame, err := data.frame(jsonCodec{})
+	assert.NoError(t, err)
+
+	for _, sid := range []string{"", "a1b2c3", `x"y`} {
+		resp := &ClientResponse{IsAck: true, Sid: sid, Event: data.message.Event, Content: data.message.Content}
+
+		expect, err := sonic.Marshal(resp)
+		assert.NoError(t, err)
+
+		resp.frame = frame
+		actual, err := resp.encode()
+		assert.NoError(t, err)
+		assert.JSONEq(t, string(expect), string(actual))
+	}
+
+	// 共享的预编码数据不被修改
+	again, _ := data.frame(jsonCodec{})
+	assert.True(t, json.Valid(again))
+	assert.NotContains(t, string(again), "sid")
+}
+
+// 每个客户端各自序列化
+func BenchmarkFanout_MarshalPerClient(b *testing.B) {
+	benchmarkFanout(b, func(data *SenderContent, sid string) ([]byte, error) {
+		return sonic.Marshal(&ClientResponse{IsAck: data.IsAck, Sid: sid, Event: data.message.Event, Content: data.message.Content})
+	})
+}
+
+// 消息体序列化一次，仅拼接 ACK ID
+func BenchmarkFanout_Frame(b *testing.B) {
+	benchmarkFanout(b, func(data *SenderContent, sid string) ([]byte, error) {
+		frame, err := data.frame(jsonCodec{})
+		if err != nil {
+			return nil, err
+		}
+
+		resp := &ClientResponse{IsAck: data.IsAck, Sid: sid, Event: data.message.Event, Content: data.message.Content, frame: frame}
+		return resp.encode()
+	})
+}
+
+// benchmarkFanout 单条广播消息推送给 500 个客户端
+func benchmarkFanout(b *testing.B, encode func(data *SenderContent, sid string) ([]byte, error)) {
+	const clients = 500
+
+	sids := make([]string, clients)
+	for i := range sids {
+		sids[i] = "7c0e4d2a9b6f4a3e8d1c5b2a7f9e" + string(rune('a'+i%26)) + "d3c"
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		data := benchSenderContent()
+		for _, sid := range sids {
+			if _, err := encode(data, sid); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
@@ -9,11 +9,6 @@ import (
 	"lingua_exchange/pkg/timewheel"
 )
 
-const (
-	heartbeatInterval = 30 // 心跳检测的间隔时间（秒）
-	heartbeatTimeout  = 75 // 心跳检测的超时时间（超时时间为间隔的2.5倍以上）
-)
-
 var health *heartbeat
 
 // heartbeat 结构体用于管理客户端的心跳检测
@@ -48,8 +43,8 @@ func (h *heartbeat) Start(ctx context.Context) error {
 // insert 添加客户端到心跳检测队列
 // c: 客户端对象
 func (h *heartbeat) insert(c *Client) {
-	// 将客户端添加到时间轮队列，等待 heartbeatInterval 秒后进行下一次检测
-	h.timeWheel.Add(strconv.FormatInt(c.cid, 10), c, time.Duration(heartbeatInterval)*time.Second)
+	// 将客户端添加到时间轮队列，按所属渠道的心跳间隔进行下一次检测
+	h.timeWheel.Add(strconv.FormatInt(c.cid, 10), c, c.channel.Options().HeartbeatInterval)
 }
 
 // delete 从心跳检测队列中移除客户端
@@ -69,20 +64,22 @@ func (h *heartbeat) handle(timeWheel *timewheel.SimpleTimeWheel[*Client], key st
 		return
 	}
 
+	options := c.channel.Options()
+
 	// 计算上次心跳时间与当前时间的差值
-	interval := int(time.Now().Unix() - c.lastTime)
+	interval := time.Since(time.Unix(c.lastTime, 0))
 
 	// 如果超时则关闭连接
-	if interval > heartbeatTimeout {
+	if interval > options.HeartbeatTimeout {
 		c.Close(2000, "心跳检测超时，连接已关闭")
 		return
 	}
 
 	// 如果超过心跳间隔时间，则推送一次 "ping" 消息作为心跳
-	if interval > heartbeatInterval {
+	if interval > options.HeartbeatInterval {
 		_ = c.Write(&ClientResponse{Event: "ping"})
 	}
 
 	// 重新将客户端添加到时间轮，等待下一次心跳检测
-	timeWheel.Add(key, c, time.Duration(heartbeatInterval)*time.Second)
+	timeWheel.Add(key, c, options.HeartbeatInterval)
 }
@@ -1,5 +1,7 @@
 package socket
 
+import "sync"
+
 // Message 表示客户端交互的消息体
 // 包含事件名称和消息内容
 type Message struct {
@@ -21,9 +23,12 @@ func NewMessage(event string, content any) *Message {
 type SenderContent struct {
 	IsAck     bool     // 是否需要消息确认 (ACK)
 	broadcast bool     // 是否是广播消息
-	exclude   []int64  // 排除的用户 ID 列表（预留字段，可扩展过滤机制）
-	receives  []int64  // 接收消息的用户 ID 列表
+	exclude   []int64  // 排除的客户端 ID 列表
+	receives  []int64  // 接收消息的客户端 ID 列表
+	users     []int    // 接收消息的用户 ID 列表，推送时解析为本节点客户端
 	message   *Message // 消息体，包含事件和内容
+
+	frames sync.Map // 按编码格式缓存的预编码消息体
 }
 
 // NewSenderContent 创建并返回 SenderContent 的实例
@@ -68,7 +73,15 @@ func (s *SenderContent) SetReceive(cid ...int64) *SenderContent {
 	return s
 }
 
-// SetExclude 设置不接收广播消息的客户端 ID 列表
+// SetReceiveUsers 添加接收消息的用户 ID 列表
+// uid: 接收消息的用户 ID，推送时解析为用户在本节点的所有客户端
+// 支持链式调用
+func (s *SenderContent) SetReceiveUsers(uid ...int) *SenderContent {
+	s.users = append(s.users, uid...)
+	return s
+}
+
+// SetExclude 设置不接收消息的客户端 ID 列表
 // cid: 需要排除的客户端 ID
 // 支持链式调用
 func (s *SenderContent) SetExclude(cid ...int64) *SenderContent {
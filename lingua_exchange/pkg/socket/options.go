@@ -0,0 +1,73 @@
+package socket
+
+import "time"
+
+// ChannelOptions 渠道配置，心跳及 ACK 策略可按终端网络环境区分
+type ChannelOptions struct {
+	Workers           int           // 渠道推送协程数
+	HeartbeatInterval time.Duration // 心跳检测的间隔时间
+	HeartbeatTimeout  time.Duration // 心跳检测的超时时间（未设置时为间隔的2.5倍，至少为间隔的2倍）
+	AckDelay          time.Duration // 首次重试延迟，之后按指数退避
+	AckMaxDelay       time.Duration // 最大重试延迟
+	AckRetry          int           // 连接存活期间的最大重试次数
+}
+
+// DefaultChannelOptions 默认渠道配置
+func DefaultChannelOptions() *ChannelOptions {
+	return &ChannelOptions{
+		Workers:           10,
+		HeartbeatInterval: 30 * time.Second,
+		HeartbeatTimeout:  75 * time.Second,
+		AckDelay:          5 * time.Second,
+		AckMaxDelay:       60 * time.Second,
+		AckRetry:          3,
+	}
+}
+
+// withDefaults 未设置的配置项使用默认值
+func (o *ChannelOptions) withDefaults() *ChannelOptions {
+	def := DefaultChannelOptions()
+	if o == nil {
+		return def
+	}
+
+	opts := *o
+	if opts.Workers <= 0 {
+		opts.Workers = def.Workers
+	}
+
+	if opts.HeartbeatInterval <= 0 {
+		opts.HeartbeatInterval = def.HeartbeatInterval
+	}
+
+	// 超时时间过短时一次心跳延迟即会断开连接，至少保留两个心跳间隔
+	if opts.HeartbeatTimeout <= 0 {
+		opts.HeartbeatTimeout = opts.HeartbeatInterval * 5 / 2
+	} else if opts.HeartbeatTimeout < opts.HeartbeatInterval*2 {
+		opts.HeartbeatTimeout = opts.HeartbeatInterval * 2
+	}
+
+	if opts.AckDelay <= 0 {
+		opts.AckDelay = def.AckDelay
+	}
+
+	if opts.AckMaxDelay < opts.AckDelay {
+		opts.AckMaxDelay = max(def.AckMaxDelay, opts.AckDelay)
+	}
+
+	if opts.AckRetry <= 0 {
+		opts.AckRetry = def.AckRetry
+	}
+
+	return &opts
+}
+
+// ackDelay 第 attempts 次发送后的重试延迟
+func (o *ChannelOptions) ackDelay(attempts int) time.Duration {
+	delay := o.AckDelay << (attempts - 1)
+	if delay <= 0 || delay > o.AckMaxDelay {
+		return o.AckMaxDelay
+	}
+
+	return delay
+}
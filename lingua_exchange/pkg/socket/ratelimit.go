@@ -0,0 +1,140 @@
+package socket
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/zhufuyi/sponge/pkg/logger"
+	"golang.org/x/time/rate"
+)
+
+const (
+	CloseCodePolicyViolation = 1008 // 客户端违反限流策略被断开
+
+	PushEventRateLimited = "rate.limited" // 上行消息被限流告警
+
+	rateLimitAnyEvent  = "*"             // 默认限流规则
+	rateViolationReset = 1 * time.Minute // 超过该时间无违规时重置违规次数
+)
+
+// RateRule 事件限流规则（令牌桶），Rate 为每秒生成的令牌数，Burst 为桶容量，Rate 为 0 时不限流
+type RateRule struct {
+	Rate  float64
+	Burst int
+}
+
+// IUserLimiter 用户级限流，多节点共享
+type IUserLimiter interface {
+	Allow(ctx context.Context, uid int, event string) (bool, error)
+}
+
+// RateLimitOptions 上行消息限流配置
+// 违规依次升级：丢弃消息，累计 WarnAfter 次后推送 rate.limited 告警，累计 CloseAfter 次后以 1008 断开连接
+type RateLimitOptions struct {
+	MaxFrameSize int                 // 单条消息最大字节数，0 为不限制
+	Rules        map[string]RateRule // 连接级限流规则，按事件名配置，* 为未配置事件的默认规则
+	WarnAfter    int                 // 推送告警前允许的违规次数，默认 3
+	CloseAfter   int                 // 断开连接前允许的违规次数，默认 20
+	UserLimiter  IUserLimiter        // 用户级限流，为空时不限制
+}
+
+// rateLimiter 单个连接的限流状态，仅在读协程中使用
+type rateLimiter struct {
+	options    *RateLimitOptions
+	buckets    map[string]*rate.Limiter
+	violations int
+	lastTime   time.Time
+}
+
+func newRateLimiter(options *RateLimitOptions) *rateLimiter {
+	if options == nil {
+		return nil
+	}
+
+	opts := *options
+	if opts.WarnAfter <= 0 {
+		opts.WarnAfter = 3
+	}
+
+	if opts.CloseAfter <= opts.WarnAfter {
+		opts.CloseAfter = max(20, opts.WarnAfter+1)
+	}
+
+	return &rateLimiter{options: &opts, buckets: make(map[string]*rate.Limiter)}
+}
+
+// bucket 获取事件对应的令牌桶，未配置规则的事件共用 * 令牌桶，避免轮换事件名绕过限流，未配置 * 规则时返回空
+func (r *rateLimiter) bucket(event string) *rate.Limiter {
+	if _, ok := r.options.Rules[event]; !ok {
+		event = rateLimitAnyEvent
+	}
+
+	if bucket, ok := r.buckets[event]; ok {
+		return bucket
+	}
+
+	rule := r.options.Rules[event]
+
+	var bucket *rate.Limiter
+	if rule.Rate > 0 {
+		bucket = rate.NewLimiter(rate.Limit(rule.Rate), max(rule.Burst, 1))
+	}
+
+	r.buckets[event] = bucket
+	return bucket
+}
+
+// allow 校验消息是否允许处理
+func (r *rateLimiter) allow(c *Client, event string) bool {
+	if bucket := r.bucket(event); bucket != nil && !bucket.Allow() {
+		return false
+	}
+
+	if r.options.UserLimiter == nil {
+		return true
+	}
+
+	ok, err := r.options.UserLimiter.Allow(context.TODO(), c.uid, event)
+	if err != nil {
+		logger.Error("[ERROR] user rate limit err: ", logger.Err(err))
+		return true
+	}
+
+	return ok
+}
+
+// violate 记录违规并按次数升级处理
+func (r *rateLimiter) violate(c *Client, event string, reason string) {
+	now := time.Now()
+	if now.Sub(r.lastTime) > rateViolationReset {
+		r.violations = 0
+	}
+	r.violations, r.lastTime = r.violations+1, now
+
+	switch {
+	case r.violations >= r.options.CloseAfter:
+		rateLimitCounter.WithLabelValues(c.channel.Name(), "closed").Inc()
+		c.Close(CloseCodePolicyViolation, "消息发送过于频繁，连接已关闭")
+	case r.violations >= r.options.WarnAfter:
+		rateLimitCounter.WithLabelValues(c.channel.Name(), "warned").Inc()
+		_ = c.Write(&ClientResponse{
+			Event: PushEventRateLimited,
+			Content: map[string]any{
+				"event":  event,
+				"reason": reason,
+			},
+		})
+	default:
+		rateLimitCounter.WithLabelValues(c.channel.Name(), "dropped").Inc()
+	}
+}
+
+// rateLimitCounter 按渠道及处理结果统计上行消息限流
+var rateLimitCounter = promauto.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "im",
+	Subsystem: "socket",
+	Name:      "client_rate_limited_total",
+	Help:      "Total number of rate limited client messages by channel and outcome.",
+}, []string{"channel", "outcome"})
@@ -0,0 +1,619 @@
+// Package sdk 聊天长连接客户端，支持 WebSocket 及 TCP 两种连接方式，
+// 自动处理心跳、消息确认(ACK)、断线重连及离线消息同步，适用于机器人、集成测试及压测工具。
+package sdk
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	writeTimeout = 10 * time.Second // 单次写入超时
+	resumeSkew   = 5 * time.Second  // 离线同步游标回退时间，重复消息按 msg_id 去重
+	dedupeSize   = 1024             // 消息去重窗口大小
+)
+
+var (
+	ErrClosed       = errors.New("socket client has been closed")
+	ErrNotConnected = errors.New("socket client is not connected")
+	ErrDisconnected = errors.New("socket connection lost before reply")
+)
+
+// Options 客户端配置
+type Options struct {
+	Network      string        // 连接类型 ws 或 tcp，默认 ws
+	Addr         string        // ws 为完整地址，例如 ws://127.0.0.1:8080/api/v1/ws/chat.io；tcp 为 host:port
+	Token        string        // 登录令牌
+	Channel      string        // 渠道名称，仅 tcp 使用，默认 chat
+	Platform     string        // 客户端平台，例如 ios、android、windows、web，用于多设备登录策略
+	Device       string        // 设备标识
+	Header       http.Header   // 握手请求头，仅 ws 使用
+	DialTimeout  time.Duration // 建立连接及等待 connect 事件的超时时间，默认 10s
+	CallTimeout  time.Duration // Call 默认超时时间，默认 10s
+	Reconnect    bool          // 是否断线自动重连
+	ReconnectMin time.Duration // 重连最小间隔，默认 1s
+	ReconnectMax time.Duration // 重连最大间隔，默认 30s
+	Resume       bool          // 重连后是否发送 sync.resume 同步离线消息
+}
+
+// Client 长连接客户端
+// 回调在读协程中按消息顺序串行执行，耗时操作需自行异步处理
+type Client struct {
+	opts *Options
+
+	mu     sync.Mutex // 保护 conn 及串行写入
+	conn   transport
+	config atomic.Pointer[Config]
+
+	hmu          sync.RWMutex
+	handlers     map[string][]func(msg *Message)
+	onConnect    func(conf *Config)
+	onDisconnect func(err error)
+	onError      func(err error)
+
+	pending sync.Map // req_id -> chan *Message
+	reqSeq  atomic.Uint64
+
+	seen     *dedupe
+	lastTime atomic.Int64 // 最后一次收到消息的时间(纳秒)
+	kicked   atomic.Bool  // 已被服务端强制断开或被新登录替换，不再自动重连
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// New 创建客户端，需调用 Connect 建立连接
+func New(opts *Options) *Client {
+	o := *opts
+	if o.Network == "" {
+		o.Network = NetworkWs
+	}
+	if o.Channel == "" {
+		o.Channel = "chat"
+	}
+	if o.DialTimeout <= 0 {
+		o.DialTimeout = 10 * time.Second
+	}
+	if o.CallTimeout <= 0 {
+		o.CallTimeout = 10 * time.Second
+	}
+	if o.ReconnectMin <= 0 {
+		o.ReconnectMin = time.Second
+	}
+	if o.ReconnectMax < o.ReconnectMin {
+		o.ReconnectMax = max(30*time.Second, o.ReconnectMin)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &Client{
+		opts:     &o,
+		handlers: make(map[string][]func(msg *Message)),
+		seen:     newDedupe(dedupeSize),
+		ctx:      ctx,
+		cancel:   cancel,
+		done:     make(chan struct{}),
+	}
+}
+
+// Connect 建立连接并等待服务端 connect 事件，连接成功后在后台读取消息
+func (c *Client) Connect(ctx context.Context) error {
+	if c.ctx.Err() != nil {
+		return ErrClosed
+	}
+
+	conn, frames, err := c.dial(ctx)
+	if err != nil {
+		return err
+	}
+
+	go c.loop(conn, frames)
+
+	return nil
+}
+
+// Close 关闭连接并停止重连
+func (c *Client) Close() error {
+	c.cancel()
+
+	c.mu.Lock()
+	conn := c.conn
+	c.mu.Unlock()
+
+	if conn != nil {
+		_ = conn.Close()
+		<-c.done
+	}
+
+	return nil
+}
+
+// Config 服务端下发的心跳及 ACK 配置，未连接时返回 nil
+func (c *Client) Config() *Config {
+	return c.config.Load()
+}
+
+// On 注册事件回调，同一事件可注册多个
+func (c *Client) On(event string, fn func(msg *Message)) {
+	c.hmu.Lock()
+	defer c.hmu.Unlock()
+
+	c.handlers[event] = append(c.handlers[event], fn)
+}
+
+// OnConnect 连接(含重连)成功回调
+func (c *Client) OnConnect(fn func(conf *Config)) {
+	c.hmu.Lock()
+	defer c.hmu.Unlock()
+
+	c.onConnect = fn
+}
+
+// OnDisconnect 连接断开回调
+func (c *Client) OnDisconnect(fn func(err error)) {
+	c.hmu.Lock()
+	defer c.hmu.Unlock()
+
+	c.onDisconnect = fn
+}
+
+// OnError 消息解析及重连失败回调
+func (c *Client) OnError(fn func(err error)) {
+	c.hmu.Lock()
+	defer c.hmu.Unlock()
+
+	c.onError = fn
+}
+
+// OnImMessage 对话消息推送，重复投递的消息已按 msg_id 去重
+func (c *Client) OnImMessage(fn func(msg *ImMessage)) {
+	on(c, EventImMessage, fn)
+}
+
+// OnImMessageKeyboard 键盘输入事件推送
+func (c *Client) OnImMessageKeyboard(fn func(msg *ImMessageKeyboard)) {
+	on(c, EventImMessageKeyboard, fn)
+}
+
+// OnImMessageRead 对话消息读事件推送
+func (c *Client) OnImMessageRead(fn func(msg *ImMessageRead)) {
+	on(c, EventImMessageRead, fn)
+}
+
+// OnImMessageRevoke 聊天消息撤销推送
+func (c *Client) OnImMessageRevoke(fn func(msg *ImMessageRevoke)) {
+	on(c, EventImMessageRevoke, fn)
+}
+
+// OnGroupApply 入群申请推送
+func (c *Client) OnGroupApply(fn func(msg *GroupApply)) {
+	on(c, EventGroupApply, fn)
+}
+
+// OnSyncResumed 离线消息同步完成通知
+func (c *Client) OnSyncResumed(fn func(msg *SyncResumed)) {
+	on(c, EventSyncResumed, fn)
+}
+
+// OnTokenExpired 连接令牌过期通知，需在宽限时间内调用 RefreshToken
+func (c *Client) OnTokenExpired(fn func(msg *TokenExpired)) {
+	on(c, EventTokenExpired, fn)
+}
+
+// OnKicked 连接被强制断开通知，之后不再自动重连
+func (c *Client) OnKicked(fn func(msg *Kicked)) {
+	on(c, EventImKicked, fn)
+}
+
+// OnSessionReplaced 连接被同平台新登录替换通知，之后不再自动重连
+func (c *Client) OnSessionReplaced(fn func(msg *SessionReplaced)) {
+	on(c, EventImSessionReplaced, fn)
+}
+
+// OnEventError 未携带 req_id 的上行事件处理失败回执
+func (c *Client) OnEventError(fn func(msg *EventReply)) {
+	on(c, EventError, fn)
+}
+
+// Emit 发送上行事件，不等待回执
+func (c *Client) Emit(event string, content any) error {
+	return c.write(&request{Event: event, Content: content})
+}
+
+// Call 发送携带 req_id 的上行事件并等待 rpc.reply，处理失败时返回 *Error
+func (c *Client) Call(ctx context.Context, event string, content any) (json.RawMessage, error) {
+	reqId := strconv.FormatUint(c.reqSeq.Add(1), 10)
+
+	ch := make(chan *Message, 1)
+	c.pending.Store(reqId, ch)
+	defer c.pending.Delete(reqId)
+
+	if err := c.write(&request{Event: event, ReqId: reqId, Content: content}); err != nil {
+		return nil, err
+	}
+
+	timer := time.NewTimer(c.opts.CallTimeout)
+	defer timer.Stop()
+
+	select {
+	case msg := <-ch:
+		if msg == nil {
+			return nil, ErrDisconnected
+		}
+
+		if msg.Code != nil && *msg.Code != 0 {
+			return nil, &Error{Code: *msg.Code, Msg: msg.Msg}
+		}
+
+		return msg.Data, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-timer.C:
+		return nil, context.DeadlineExceeded
+	case <-c.ctx.Done():
+		return nil, ErrClosed
+	}
+}
+
+// RefreshToken 续期连接令牌，后续重连使用新令牌
+func (c *Client) RefreshToken(token string) error {
+	c.mu.Lock()
+	c.opts.Token = token
+	c.mu.Unlock()
+
+	return c.Emit(EventTokenRefresh, map[string]string{"token": token})
+}
+
+// dial 建立连接并等待 connect 事件，之前收到的消息在连接成功后处理
+func (c *Client) dial(ctx context.Context) (transport, [][]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.opts.DialTimeout)
+	defer cancel()
+
+	c.mu.Lock()
+	opts := *c.opts
+	c.mu.Unlock()
+
+	conn, err := dial(ctx, &opts)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// 读取阻塞时通过关闭连接退出
+	stop := context.AfterFunc(ctx, func() { _ = conn.Close() })
+	defer stop()
+
+	frames := make([][]byte, 0)
+	for {
+		data, err := conn.Read()
+		if err != nil {
+			if ctx.Err() != nil {
+				err = ctx.Err()
+			}
+			_ = conn.Close()
+			return nil, nil, err
+		}
+
+		msg := &Message{}
+		if err := json.Unmarshal(data, msg); err != nil {
+			_ = conn.Close()
+			return nil, nil, err
+		}
+
+		switch msg.Event {
+		case EventConnect:
+			conf := &Config{}
+			_ = json.Unmarshal(msg.Content, conf)
+			c.config.Store(conf)
+			return conn, frames, nil
+		case EventError:
+			_ = conn.Close()
+
+			reply := &EventReply{}
+			_ = json.Unmarshal(msg.Content, reply)
+			return nil, nil, &Error{Code: reply.Code, Msg: reply.Msg}
+		default:
+			frames = append(frames, data)
+		}
+	}
+}
+
+// loop 读取消息，断线后按配置重连
+func (c *Client) loop(conn transport, frames [][]byte) {
+	defer close(c.done)
+
+	resume := false
+	for {
+		err := c.serve(conn, frames, resume)
+
+		c.mu.Lock()
+		c.conn = nil
+		c.mu.Unlock()
+		_ = conn.Close()
+
+		c.failPending()
+		c.emitDisconnect(err)
+
+		if c.ctx.Err() != nil || !c.opts.Reconnect || c.kicked.Load() {
+			return
+		}
+
+		conn, frames = c.reconnect()
+		if conn == nil {
+			return
+		}
+
+		resume = c.opts.Resume
+	}
+}
+
+// serve 处理单个连接直至断开
+func (c *Client) serve(conn transport, frames [][]byte, resume bool) error {
+	c.mu.Lock()
+	c.conn = conn
+	c.mu.Unlock()
+
+	if c.ctx.Err() != nil {
+		return ErrClosed
+	}
+
+	c.lastTime.Store(time.Now().UnixNano())
+
+	conf := c.config.Load()
+
+	stop := make(chan struct{})
+	defer close(stop)
+	go c.heartbeat(conn, conf, stop)
+
+	c.hmu.RLock()
+	onConnect := c.onConnect
+	c.hmu.RUnlock()
+	if onConnect != nil {
+		onConnect(conf)
+	}
+
+	if resume {
+		c.resume()
+	}
+
+	for _, data := range frames {
+		c.handle(data)
+	}
+
+	for {
+		data, err := conn.Read()
+		if err != nil {
+			return err
+		}
+
+		c.handle(data)
+	}
+}
+
+// heartbeat 按服务端配置定时发送 ping，超时未收到任何消息时断开连接触发重连
+func (c *Client) heartbeat(conn transport, conf *Config, stop chan struct{}) {
+	if conf == nil || conf.PingInterval <= 0 {
+		return
+	}
+
+	interval := time.Duration(conf.PingInterval) * time.Second
+	timeout := time.Duration(conf.PingTimeout)*time.Second + interval
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			if conf.PingTimeout > 0 && time.Since(time.Unix(0, c.lastTime.Load())) > timeout {
+				_ = conn.Close()
+				return
+			}
+
+			_ = c.write(&request{Event: EventPing})
+		}
+	}
+}
+
+// resume 以断线时间为游标同步离线消息
+func (c *Client) resume() {
+	last := time.Unix(0, c.lastTime.Load()).Add(-resumeSkew)
+
+	if err := c.Emit(EventSyncResume, map[string]int64{"sync_time": last.Unix()}); err != nil {
+		c.emitError(err)
+	}
+}
+
+// reconnect 指数退避重连，客户端关闭时返回 nil
+func (c *Client) reconnect() (transport, [][]byte) {
+	delay := c.opts.ReconnectMin
+
+	for {
+		wait := delay + time.Duration(rand.Int63n(int64(delay)/2+1))
+
+		select {
+		case <-c.ctx.Done():
+			return nil, nil
+		case <-time.After(wait):
+		}
+
+		conn, frames, err := c.dial(c.ctx)
+		if err == nil {
+			return conn, frames
+		}
+
+		c.emitError(err)
+
+		delay = min(delay*2, c.opts.ReconnectMax)
+	}
+}
+
+// handle 处理单条下行消息
+func (c *Client) handle(data []byte) {
+	msg := &Message{}
+	if err := json.Unmarshal(data, msg); err != nil {
+		c.emitError(err)
+		return
+	}
+
+	c.lastTime.Store(time.Now().UnixNano())
+
+	// 先确认再处理，避免回调耗时导致服务端重复投递
+	if msg.Sid != "" {
+		_ = c.write(&request{Event: EventAck, Sid: msg.Sid})
+	}
+
+	switch msg.Event {
+	case EventPing:
+		_ = c.write(&request{Event: EventPong})
+		return
+	case EventPong:
+		return
+	case EventConnect:
+		conf := &Config{}
+		if err := json.Unmarshal(msg.Content, conf); err == nil {
+			c.config.Store(conf)
+		}
+		return
+	case EventRpcReply:
+		if ch, ok := c.pending.LoadAndDelete(msg.ReqId); ok {
+			ch.(chan *Message) <- msg
+		}
+		return
+	case EventImMessage:
+		if c.duplicate(msg) {
+			return
+		}
+	case EventImKicked, EventImSessionReplaced:
+		c.kicked.Store(true)
+	}
+
+	c.hmu.RLock()
+	handlers := c.handlers[msg.Event]
+	c.hmu.RUnlock()
+
+	for _, fn := range handlers {
+		fn(msg)
+	}
+}
+
+// duplicate 重连同步及 ACK 重发可能重复投递对话消息
+func (c *Client) duplicate(msg *Message) bool {
+	var content struct {
+		Data struct {
+			MsgId string `json:"msg_id"`
+		} `json:"data"`
+	}
+
+	if err := json.Unmarshal(msg.Content, &content); err != nil || content.Data.MsgId == "" {
+		return false
+	}
+
+	return !c.seen.add(content.Data.MsgId)
+}
+
+func (c *Client) write(req *request) error {
+	data, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.conn == nil {
+		if c.ctx.Err() != nil {
+			return ErrClosed
+		}
+		return ErrNotConnected
+	}
+
+	return c.conn.Write(data)
+}
+
+// failPending 连接断开时结束所有等待中的请求
+func (c *Client) failPending() {
+	c.pending.Range(func(key, value any) bool {
+		c.pending.Delete(key)
+
+		select {
+		case value.(chan *Message) <- nil:
+		default:
+		}
+		return true
+	})
+}
+
+func (c *Client) emitDisconnect(err error) {
+	c.hmu.RLock()
+	fn := c.onDisconnect
+	c.hmu.RUnlock()
+
+	if fn != nil {
+		fn(err)
+	}
+}
+
+func (c *Client) emitError(err error) {
+	c.hmu.RLock()
+	fn := c.onError
+	c.hmu.RUnlock()
+
+	if fn != nil {
+		fn(err)
+	}
+}
+
+// on 注册类型化的事件回调
+func on[T any](c *Client, event string, fn func(msg *T)) {
+	c.On(event, func(msg *Message) {
+		value := new(T)
+		if err := json.Unmarshal(msg.Content, value); err != nil {
+			c.emitError(err)
+			return
+		}
+
+		fn(value)
+	})
+}
+
+// dedupe 固定窗口的消息去重
+type dedupe struct {
+	mu    sync.Mutex
+	items map[string]struct{}
+	ring  []string
+	index int
+}
+
+func newDedupe(size int) *dedupe {
+	return &dedupe{items: make(map[string]struct{}, size), ring: make([]string, size)}
+}
+
+// add 返回 false 表示已存在
+func (d *dedupe) add(id string) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if _, ok := d.items[id]; ok {
+		return false
+	}
+
+	if old := d.ring[d.index]; old != "" {
+		delete(d.items, old)
+	}
+
+	d.ring[d.index] = id
+	d.items[id] = struct{}{}
+	d.index = (d.index + 1) % len(d.ring)
+
+	return true
+}
@@ -0,0 +1,166 @@
+package sdk
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+)
+
+// 第一次连接推送消息并处理请求后断开，第二次连接校验离线同步并重复推送
+func TestClient(t *testing.T) {
+	upgrader := websocket.Upgrader{}
+	frames := make(chan string, 16)
+
+	var conns atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		n := conns.Add(1)
+
+		_ = conn.WriteMessage(websocket.TextMessage, []byte(`{"event":"connect","content":{"ping_interval":30,"ping_timeout":75,"ack_delay":5,"ack_retry":3}}`))
+		_ = conn.WriteMessage(websocket.TextMessage, []byte(`{"sid":"s1","event":"im.message","content":{"sender_id":1,"receiver_id":2,"talk_type":1,"data":{"msg_id":"m1","sequence":1}}}`))
+
+		for {
+			_, data, err := conn.ReadMessage()
+			if err != nil {
+				return
+			}
+
+			frames <- string(data)
+
+			var req request
+			_ = json.Unmarshal(data, &req)
+
+			switch {
+			case req.Event == EventTalkSessionList:
+				_ = conn.WriteMessage(websocket.TextMessage, []byte(`{"event":"rpc.reply","req_id":"`+req.ReqId+`","code":0,"msg":"ok","data":{"items":[]}}`))
+			case req.Event == EventTalkRecords:
+				_ = conn.WriteMessage(websocket.TextMessage, []byte(`{"event":"rpc.reply","req_id":"`+req.ReqId+`","code":10001,"msg":"params error"}`))
+				if n == 1 {
+					return
+				}
+			case req.Event == EventSyncResume:
+				_ = conn.WriteMessage(websocket.TextMessage, []byte(`{"event":"im.message","content":{"data":{"msg_id":"m2","sequence":2}}}`))
+			}
+		}
+	}))
+	defer server.Close()
+
+	client := New(&Options{
+		Addr:         "ws" + strings.TrimPrefix(server.URL, "http"),
+		Token:        "token",
+		Reconnect:    true,
+		ReconnectMin: 10 * time.Millisecond,
+		Resume:       true,
+	})
+
+	messages := make(chan *ImMessage, 4)
+	client.OnImMessage(func(msg *ImMessage) {
+		messages <- msg
+	})
+
+	connected := make(chan struct{}, 2)
+	client.OnConnect(func(conf *Config) {
+		connected <- struct{}{}
+	})
+
+	assert.NoError(t, client.Connect(context.Background()))
+	assert.Equal(t, 30, client.Config().PingInterval)
+
+	msg := <-messages
+	assert.Equal(t, "m1", msg.Data.MsgId)
+	assert.JSONEq(t, `{"event":"ack","sid":"s1"}`, <-frames)
+
+	data, err := client.Call(context.Background(), EventTalkSessionList, nil)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"items":[]}`, string(data))
+	<-frames
+
+	_, err = client.Call(context.Background(), EventTalkRecords, map[string]int{"talk_type": 1})
+	assert.Equal(t, &Error{Code: 10001, Msg: "params error"}, err)
+	<-frames
+
+	// 重连后发送离线同步，重复的 m1 被丢弃
+	<-connected
+	<-connected
+	events := make([]string, 0, 2)
+	for i := 0; i < 2; i++ {
+		var req request
+		assert.NoError(t, json.Unmarshal([]byte(<-frames), &req))
+		events = append(events, req.Event)
+	}
+	assert.ElementsMatch(t, []string{EventSyncResume, EventAck}, events)
+
+	msg = <-messages
+	assert.Equal(t, "m2", msg.Data.MsgId)
+	assert.Len(t, messages, 0)
+
+	assert.NoError(t, client.Close())
+	assert.ErrorIs(t, client.Emit(EventPing, nil), ErrClosed)
+
+	// 令牌错误时握手失败
+	err = New(&Options{Addr: "ws" + strings.TrimPrefix(server.URL, "http")}).Connect(context.Background())
+	assert.ErrorContains(t, err, "status: 401")
+}
+
+// 被强制断开后不再自动重连
+func TestClient_Kicked(t *testing.T) {
+	upgrader := websocket.Upgrader{}
+
+	var conns atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		conns.Add(1)
+
+		_ = conn.WriteMessage(websocket.TextMessage, []byte(`{"event":"connect","content":{"ping_interval":30,"ping_timeout":75}}`))
+		_ = conn.WriteMessage(websocket.TextMessage, []byte(`{"event":"im.kicked","content":{"reason":"banned"}}`))
+		_ = conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(4003, "kicked: banned"))
+	}))
+	defer server.Close()
+
+	client := New(&Options{
+		Addr:         "ws" + strings.TrimPrefix(server.URL, "http"),
+		Reconnect:    true,
+		ReconnectMin: 10 * time.Millisecond,
+	})
+
+	kicked := make(chan *Kicked, 1)
+	client.OnKicked(func(msg *Kicked) {
+		kicked <- msg
+	})
+
+	disconnected := make(chan struct{})
+	client.OnDisconnect(func(err error) {
+		close(disconnected)
+	})
+
+	assert.NoError(t, client.Connect(context.Background()))
+	assert.Equal(t, "banned", (<-kicked).Reason)
+	<-disconnected
+
+	time.Sleep(100 * time.Millisecond)
+	assert.Equal(t, int32(1), conns.Load())
+	assert.NoError(t, client.Close())
+}
@@ -0,0 +1,144 @@
+package sdk
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"lingua_exchange/pkg/socket/adapter/encoding"
+)
+
+const (
+	NetworkWs  = "ws"
+	NetworkTcp = "tcp"
+)
+
+// transport 底层连接，Write 需由调用方保证串行
+type transport interface {
+	Read() ([]byte, error)
+	Write(data []byte) error
+	Close() error
+}
+
+// dial 按连接类型建立连接
+func dial(ctx context.Context, opts *Options) (transport, error) {
+	switch opts.Network {
+	case NetworkTcp:
+		return dialTcp(ctx, opts)
+	default:
+		return dialWs(ctx, opts)
+	}
+}
+
+// wsTransport WebSocket 连接，令牌通过 Authorization 请求头传递
+type wsTransport struct {
+	conn *websocket.Conn
+}
+
+func dialWs(ctx context.Context, opts *Options) (transport, error) {
+	header := http.Header{}
+	for key, values := range opts.Header {
+		header[key] = values
+	}
+	header.Set("Authorization", "Bearer "+opts.Token)
+	if opts.Platform != "" {
+		header.Set("platform", opts.Platform)
+	}
+	if opts.Device != "" {
+		header.Set("deviceToken", opts.Device)
+	}
+
+	dialer := &websocket.Dialer{
+		Proxy:            http.ProxyFromEnvironment,
+		HandshakeTimeout: opts.DialTimeout,
+	}
+
+	conn, resp, err := dialer.DialContext(ctx, opts.Addr, header)
+	if err != nil {
+		if resp != nil {
+			return nil, fmt.Errorf("websocket handshake failed, status: %d, %w", resp.StatusCode, err)
+		}
+		return nil, err
+	}
+
+	return &wsTransport{conn: conn}, nil
+}
+
+func (w *wsTransport) Read() ([]byte, error) {
+	_, data, err := w.conn.ReadMessage()
+	return data, err
+}
+
+func (w *wsTransport) Write(data []byte) error {
+	_ = w.conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+	return w.conn.WriteMessage(websocket.TextMessage, data)
+}
+
+func (w *wsTransport) Close() error {
+	_ = w.conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""), time.Now().Add(time.Second))
+	return w.conn.Close()
+}
+
+// tcpTransport TCP 连接，每帧为 4 字节小端长度加消息体，首帧为授权信息
+type tcpTransport struct {
+	conn   net.Conn
+	reader *bufio.Reader
+	once   sync.Once
+}
+
+type tcpAuthorize struct {
+	Token    string `json:"token"`
+	Channel  string `json:"channel"`
+	Codec    string `json:"codec"`
+	Platform string `json:"platform,omitempty"`
+	Device   string `json:"device,omitempty"`
+}
+
+func dialTcp(ctx context.Context, opts *Options) (transport, error) {
+	dialer := &net.Dialer{Timeout: opts.DialTimeout}
+
+	conn, err := dialer.DialContext(ctx, "tcp", opts.Addr)
+	if err != nil {
+		return nil, err
+	}
+
+	t := &tcpTransport{conn: conn, reader: bufio.NewReader(conn)}
+
+	auth, _ := json.Marshal(&tcpAuthorize{Token: opts.Token, Channel: opts.Channel, Codec: "json", Platform: opts.Platform, Device: opts.Device})
+	if err := t.Write(auth); err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+
+	return t, nil
+}
+
+func (t *tcpTransport) Read() ([]byte, error) {
+	return encoding.NewDecode(t.reader)
+}
+
+func (t *tcpTransport) Write(data []byte) error {
+	frame, err := encoding.NewEncode(data)
+	if err != nil {
+		return err
+	}
+
+	_ = t.conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+	_, err = t.conn.Write(frame)
+	return err
+}
+
+func (t *tcpTransport) Close() error {
+	var err error
+	t.once.Do(func() {
+		err = t.conn.Close()
+	})
+	return err
+}
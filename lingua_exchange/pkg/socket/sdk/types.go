@@ -0,0 +1,167 @@
+package sdk
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// 服务端下行事件
+const (
+	EventConnect           = "connect"             // 连接建立，推送心跳及 ACK 配置
+	EventPing              = "ping"                // 心跳
+	EventPong              = "pong"                // 心跳回复
+	EventAck               = "ack"                 // 消息确认
+	EventImMessage         = "im.message"          // 对话消息推送
+	EventImMessageKeyboard = "im.message.keyboard" // 键盘输入事件推送
+	EventImMessageRead     = "im.message.read"     // 对话消息读事件推送
+	EventImMessageRevoke   = "im.message.revoke"   // 聊天消息撤销推送
+	EventContactApply      = "im.contact.apply"    // 好友申请消息推送
+	EventContactStatus     = "im.contact.status"   // 用户在线状态推送
+	EventGroupApply        = "im.group.apply"      // 入群申请推送
+	EventSuccess           = "event.success"       // 客户端事件处理成功回执
+	EventError             = "event.error"         // 客户端事件处理失败回执
+	EventTokenExpired      = "token.expired"       // 连接令牌过期通知
+	EventSyncResumed       = "sync.resumed"        // 离线消息同步完成通知
+	EventRpcReply          = "rpc.reply"           // 携带 req_id 的客户端事件处理回执
+	EventRateLimited       = "rate.limited"        // 上行消息被限流通知
+	EventImKicked          = "im.kicked"           // 连接被强制断开通知
+	EventImSessionReplaced = "im.session.replaced" // 连接被同平台新登录替换通知
+)
+
+// 客户端上行事件
+const (
+	EventImMessagePublish = "im.message.publish" // 发送聊天消息
+	EventTokenRefresh     = "token.refresh"      // 续期连接令牌
+	EventSyncResume       = "sync.resume"        // 断线重连同步离线消息
+	EventTalkSessionList  = "talk.session.list"  // 获取会话列表
+	EventTalkRecords      = "talk.records"       // 分页获取聊天记录
+)
+
+// Message 服务端下行消息
+type Message struct {
+	Sid     string          `json:"sid,omitempty"`     // 需确认的消息ID
+	Event   string          `json:"event"`             // 事件名称
+	Content json.RawMessage `json:"content,omitempty"` // 事件内容
+	ReqId   string          `json:"req_id,omitempty"`  // 请求ID，仅 rpc.reply 使用
+	Code    *int            `json:"code,omitempty"`    // 错误码，仅 rpc.reply 使用
+	Msg     string          `json:"msg,omitempty"`     // 错误描述，仅 rpc.reply 使用
+	Data    json.RawMessage `json:"data,omitempty"`    // 返回数据，仅 rpc.reply 使用
+}
+
+// request 客户端上行消息
+type request struct {
+	Event   string `json:"event"`
+	Sid     string `json:"sid,omitempty"`
+	ReqId   string `json:"req_id,omitempty"`
+	Content any    `json:"content,omitempty"`
+}
+
+// Config 连接建立时服务端下发的配置
+type Config struct {
+	PingInterval int `json:"ping_interval"` // 心跳间隔(秒)
+	PingTimeout  int `json:"ping_timeout"`  // 心跳超时(秒)
+	AckDelay     int `json:"ack_delay"`     // ACK 重发间隔(秒)
+	AckRetry     int `json:"ack_retry"`     // ACK 最大重发次数
+}
+
+// TalkRecord 聊天记录
+type TalkRecord struct {
+	ID         int    `json:"id"`
+	MsgId      string `json:"msg_id"`
+	Sequence   int    `json:"sequence"`
+	TalkType   int    `json:"talk_type"`
+	MsgType    int    `json:"msg_type"`
+	UserId     int    `json:"user_id"`
+	ReceiverId int    `json:"receiver_id"`
+	Nickname   string `json:"nickname"`
+	Avatar     string `json:"avatar"`
+	IsRevoke   int    `json:"is_revoke"`
+	IsMark     int    `json:"is_mark"`
+	IsRead     int    `json:"is_read"`
+	CreatedAt  string `json:"created_at"`
+	Extra      any    `json:"extra"` // 额外参数
+}
+
+// ImMessage 对话消息推送
+type ImMessage struct {
+	SenderId   int         `json:"sender_id"`
+	ReceiverId int         `json:"receiver_id"`
+	TalkType   int         `json:"talk_type"`
+	Data       *TalkRecord `json:"data"`
+}
+
+// ImMessageKeyboard 键盘输入事件推送
+type ImMessageKeyboard struct {
+	SenderId   int `json:"sender_id"`
+	ReceiverId int `json:"receiver_id"`
+}
+
+// ImMessageRead 对话消息读事件推送
+type ImMessageRead struct {
+	SenderId   int      `json:"sender_id"`
+	ReceiverId int      `json:"receiver_id"`
+	MsgIds     []string `json:"msg_ids"`
+}
+
+// ImMessageRevoke 聊天消息撤销推送
+type ImMessageRevoke struct {
+	TalkType   int    `json:"talk_type"`
+	SenderId   int    `json:"sender_id"`
+	ReceiverId int    `json:"receiver_id"`
+	MsgId      string `json:"msg_id"`
+	Text       string `json:"text"`
+}
+
+// GroupApply 入群申请推送
+type GroupApply struct {
+	GroupName string `json:"group_name"`
+	Username  string `json:"username"`
+}
+
+// SyncResumed 离线消息同步完成通知
+type SyncResumed struct {
+	Sessions []*SyncResumedSession `json:"sessions"`
+}
+
+// SyncResumedSession 会话同步结果
+type SyncResumedSession struct {
+	TalkType   int  `json:"talk_type"`
+	ReceiverId int  `json:"receiver_id"`
+	Sequence   int  `json:"sequence"` // 已同步到的消息时序
+	Count      int  `json:"count"`    // 本次补发的消息数
+	HasMore    bool `json:"has_more"` // 超出补发上限，剩余消息需通过历史记录接口拉取
+}
+
+// TokenExpired 连接令牌过期通知
+type TokenExpired struct {
+	Grace int `json:"grace"` // 宽限时间(秒)，超时未续期将断开连接
+}
+
+// Kicked 连接被强制断开通知
+type Kicked struct {
+	Reason string `json:"reason"` // admin、logout、password_reset、banned
+}
+
+// SessionReplaced 连接被同平台新登录替换通知
+type SessionReplaced struct {
+	Platform string `json:"platform"` // 新连接的平台
+	Device   string `json:"device"`   // 新连接的设备标识
+}
+
+// EventReply 客户端事件处理回执
+type EventReply struct {
+	Event string          `json:"event"`          // 客户端上行事件名
+	Code  int             `json:"code"`           // 错误码，0 表示成功
+	Msg   string          `json:"msg"`            // 错误描述
+	Data  json.RawMessage `json:"data,omitempty"` // 返回数据
+}
+
+// Error 服务端返回的事件处理错误
+type Error struct {
+	Code int
+	Msg  string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("socket event error, code: %d, msg: %s", e.Code, e.Msg)
+}
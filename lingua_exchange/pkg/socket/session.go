@@ -2,6 +2,8 @@ package socket
 
 import (
 	"context"
+	"fmt"
+	"sort"
 	"sync"
 	"time"
 
@@ -13,38 +15,117 @@ var Session *session
 
 var once sync.Once
 
+// 默认渠道消息缓冲区大小
+const defaultChannelBuffer = 1000
+
 // session 渠道客户端结构
 type session struct {
 	Chat *Channel // 默认分组
-	// Example *Channel // 示例分组
 
+	mu       sync.RWMutex
 	channels map[string]*Channel
+	watchers []func(ch *Channel) // 渠道注册回调
+
+	ctx     context.Context
+	eg      *errgroup.Group
+	fn      func(name string)
+	started bool // 渠道协程是否已启动
 }
 
 // Channel 获取指定名称的渠道
 func (s *session) Channel(name string) (*Channel, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
 	val, ok := s.channels[name]
 	return val, ok
 }
 
+// Channels 获取所有渠道，按名称排序
+func (s *session) Channels() []*Channel {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	items := make([]*Channel, 0, len(s.channels))
+	for _, ch := range s.channels {
+		items = append(items, ch)
+	}
+
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].Name() < items[j].Name()
+	})
+
+	return items
+}
+
+// Register 注册渠道，可在启动时或运行期间调用
+// buffer 为渠道消息缓冲区大小，options 为空时使用默认配置
+func (s *session) Register(name string, buffer int, options *ChannelOptions) (*Channel, error) {
+	if buffer <= 0 {
+		buffer = defaultChannelBuffer
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.channels[name]; ok {
+		return nil, fmt.Errorf("channel %s already registered", name)
+	}
+
+	ch := NewChannel(name, make(chan *SenderContent, buffer), options)
+	s.channels[name] = ch
+
+	if s.started {
+		s.start(ch)
+	}
+
+	for _, fn := range s.watchers {
+		fn(ch)
+	}
+
+	return ch, nil
+}
+
+// Watch 对已注册及之后注册的渠道执行回调
+func (s *session) Watch(fn func(ch *Channel)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, ch := range s.channels {
+		fn(ch)
+	}
+
+	s.watchers = append(s.watchers, fn)
+}
+
+// start 启动渠道协程
+func (s *session) start(ch *Channel) {
+	s.eg.Go(func() error {
+		defer s.fn(ch.Name() + " exit")
+		return ch.Start(s.ctx)
+	})
+}
+
 // Initialize 初始化 Session 并启动所需的服务和协程
-func Initialize(ctx context.Context, eg *errgroup.Group, fn func(name string)) {
+// chat 为默认渠道的配置，为空时使用默认配置
+func Initialize(ctx context.Context, eg *errgroup.Group, fn func(name string), chat *ChannelOptions) {
 	once.Do(func() {
-		InitAck()               // 初始化 AckBuffer
-		initialize(ctx, eg, fn) // 实际初始化逻辑
+		InitAck()                     // 初始化 AckBuffer
+		initialize(ctx, eg, fn, chat) // 实际初始化逻辑
 	})
 }
 
-// initialize 内部初始化逻辑，创建默认 Chat 和 Example 渠道并启动各项服务
-func initialize(ctx context.Context, eg *errgroup.Group, fn func(name string)) {
+// initialize 内部初始化逻辑，创建默认 Chat 渠道并启动各项服务
+func initialize(ctx context.Context, eg *errgroup.Group, fn func(name string), chat *ChannelOptions) {
 	Session = &session{
-		Chat: NewChannel("chat", make(chan *SenderContent, 5<<20)), // 创建 chat 渠道，缓冲区为 5MB
-		// Example:  NewChannel("example", make(chan *SenderContent, 100)), // 创建 example 渠道，缓冲区为 100
 		channels: map[string]*Channel{},
+		ctx:      ctx,
+		eg:       eg,
+		fn:       fn,
 	}
 
-	Session.channels["chat"] = Session.Chat
-	// Session.channels["example"] = Session.Example
+	// 创建 chat 渠道，缓冲区为 5MB
+	Session.Chat, _ = Session.Register("chat", 5<<20, chat)
 
 	// 延时 3 秒启动守护协程
 	time.AfterFunc(3*time.Second, func() {
@@ -60,16 +141,14 @@ func initialize(ctx context.Context, eg *errgroup.Group, fn func(name string)) {
 			return ack.Start(ctx)
 		})
 
-		// 启动 chat 渠道协程
-		eg.Go(func() error {
-			defer fn("chat exit")
-			return Session.Chat.Start(ctx)
-		})
+		// 启动已注册的渠道协程，之后注册的渠道在注册时启动
+		Session.mu.Lock()
+		defer Session.mu.Unlock()
+
+		for _, ch := range Session.channels {
+			Session.start(ch)
+		}
 
-		// 启动 example 渠道协程
-		// eg.Go(func() error {
-		// 	defer fn("example exit")
-		// 	return Session.Example.Start(ctx)
-		// })
+		Session.started = true
 	})
 }
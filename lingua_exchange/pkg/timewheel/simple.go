@@ -10,7 +10,7 @@ import (
 type entry[T any] struct {
 	key    string
 	value  T
-	expire int64
+	expire int64 // 到期时间(毫秒级时间戳)
 }
 
 // SimpleTimeWheel 简单时间轮
@@ -93,11 +93,11 @@ func (t *SimpleTimeWheel[T]) run() {
 				t.indicator.Remove(v.key)
 
 				worker.Go(func() {
-					unix := time.Now().Unix()
-					if v.expire <= unix {
+					now := time.Now().UnixMilli()
+					if v.expire <= now {
 						t.onTick(t, v.key, v.value)
 					} else {
-						t.Add(v.key, v.value, time.Duration(v.expire-unix)*time.Second)
+						t.Add(v.key, v.value, time.Duration(v.expire-now)*time.Millisecond)
 					}
 				})
 			}
@@ -105,9 +105,9 @@ func (t *SimpleTimeWheel[T]) run() {
 	}
 }
 
-// Add 添加任务
+// Add 添加任务，每个任务可使用不同的延迟时间，超过一圈的任务到达槽位后会重新计算
 func (t *SimpleTimeWheel[T]) Add(key string, value T, delay time.Duration) {
-	t.taskChan <- &entry[T]{key: key, value: value, expire: time.Now().Add(delay).Unix()}
+	t.taskChan <- &entry[T]{key: key, value: value, expire: time.Now().Add(delay).UnixMilli()}
 }
 
 func (t *SimpleTimeWheel[T]) Remove(key string) {
@@ -119,10 +119,10 @@ func (t *SimpleTimeWheel[T]) Remove(key string) {
 
 func (t *SimpleTimeWheel[T]) getCircleAndSlot(e *entry[T]) int {
 
-	remainingTime := int(e.expire - time.Now().Unix())
+	remainingTime := e.expire - time.Now().UnixMilli()
 	if remainingTime <= 0 {
 		remainingTime = 0
 	}
 
-	return (t.tickIndex + remainingTime/int(t.interval.Seconds())) % len(t.slot)
+	return (t.tickIndex + int(remainingTime/max(t.interval.Milliseconds(), 1))) % len(t.slot)
 }
@@ -194,6 +194,27 @@ CREATE TABLE `talk_records_read`
 ) ENGINE = InnoDB
   DEFAULT CHARSET = utf8mb4 COMMENT ='用户已读列表';
 
+-- 消息推送发件箱表
+CREATE TABLE talk_message_outbox
+(
+    id          BIGINT unsigned     NOT NULL AUTO_INCREMENT COMMENT '自增ID',
+    msg_id      varchar(64)         NOT NULL DEFAULT '' COMMENT '消息ID',
+    talk_type   int(11) unsigned    NOT NULL DEFAULT '1' COMMENT '对话类型[1:私信;2:群聊;]',
+    user_id     BIGINT unsigned     NOT NULL DEFAULT '0' COMMENT '发送者ID',
+    receiver_id int(11) unsigned    NOT NULL DEFAULT '0' COMMENT '接收者ID（用户ID 或 群ID）',
+    event       varchar(64)         NOT NULL DEFAULT '' COMMENT '订阅事件名',
+    payload     text                NOT NULL COMMENT '订阅消息内容',
+    status      tinyint(4) unsigned NOT NULL DEFAULT '0' COMMENT '投递状态[0:待投递;1:已投递;2:投递失败;]',
+    retry       int(11) unsigned    NOT NULL DEFAULT '0' COMMENT '重试次数',
+    created_at  datetime            NOT NULL COMMENT '创建时间',
+    updated_at  datetime            NOT NULL COMMENT '更新时间',
+    deleted_at  datetime            null,
+    PRIMARY KEY (id),
+    KEY idx_status_created_at (status, created_at) USING BTREE,
+    KEY idx_msg_id (msg_id) USING BTREE
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8mb4 COMMENT ='消息推送发件箱';
+
 CREATE TABLE talk_records_vote
 (
     `id`            int(11) unsigned     NOT NULL AUTO_INCREMENT COMMENT '投票ID',